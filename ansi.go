@@ -0,0 +1,62 @@
+package marker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiRegexp matches CSI sequences (such as SGR colors), OSC sequences and two byte escape sequences
+var ansiRegexp = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[0-~])`)
+
+// escape is an escape sequence located at a byte offset of the visible text
+type escape struct {
+	offset   int
+	sequence string
+}
+
+// splitEscapes separates the escape sequences from the visible text of given string
+func splitEscapes(str string) (string, []escape) {
+	indexes := ansiRegexp.FindAllStringIndex(str, -1)
+	if len(indexes) == 0 {
+		return str, nil
+	}
+
+	var visible strings.Builder
+	escapes := make([]escape, 0, len(indexes))
+	last := 0
+	for _, index := range indexes {
+		start, end := index[0], index[1]
+		visible.WriteString(str[last:start])
+		escapes = append(escapes, escape{offset: visible.Len(), sequence: str[start:end]})
+		last = end
+	}
+	visible.WriteString(str[last:])
+	return visible.String(), escapes
+}
+
+// sgrParameters returns the parameters of given Select Graphic Rendition sequence and whether it is one
+func sgrParameters(sequence string) ([]string, bool) {
+	if !strings.HasPrefix(sequence, "\x1b[") || !strings.HasSuffix(sequence, "m") {
+		return nil, false
+	}
+	parameters := sequence[2 : len(sequence)-1]
+	if strings.Trim(parameters, "0123456789;:") != "" {
+		return nil, false
+	}
+	return strings.Split(parameters, ";"), true
+}
+
+// resetsGraphics reports whether given parameters of an SGR sequence turn off all attributes and colors
+func resetsGraphics(parameters []string) bool {
+	for i := 0; i < len(parameters); i++ {
+		switch strings.TrimLeft(parameters[i], "0") {
+		case "":
+			return true
+		case "38", "48", "58":
+			// extended colors carry their own arguments, e.g. 38;5;0 or 38;2;0;0;0
+			_, n := extendedColor(parameters[i+1:])
+			i += n
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitEscapes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No escapes", func(t *testing.T) {
+		visible, escapes := splitEscapes("Skydome is a data company.")
+		assert.Equal(t, "Skydome is a data company.", visible)
+		assert.Empty(t, escapes)
+	})
+
+	t.Run("SGR escapes", func(t *testing.T) {
+		visible, escapes := splitEscapes("\x1b[31mSkydome\x1b[0m is a \x1b[1;34mdata\x1b[0m company.")
+		assert.Equal(t, "Skydome is a data company.", visible)
+		expected := []escape{
+			{offset: 0, sequence: "\x1b[31m"},
+			{offset: 7, sequence: "\x1b[0m"},
+			{offset: 13, sequence: "\x1b[1;34m"},
+			{offset: 17, sequence: "\x1b[0m"},
+		}
+		assert.Equal(t, expected, escapes)
+	})
+
+	t.Run("Other escapes", func(t *testing.T) {
+		visible, escapes := splitEscapes("\x1b]0;title\x07\x1b[2KSkydome\x1b7")
+		assert.Equal(t, "Skydome", visible)
+		expected := []escape{
+			{offset: 0, sequence: "\x1b]0;title\x07"},
+			{offset: 0, sequence: "\x1b[2K"},
+			{offset: 7, sequence: "\x1b7"},
+		}
+		assert.Equal(t, expected, escapes)
+	})
+}
+
+func Test_resetsGraphics(t *testing.T) {
+	tests := []struct {
+		sequence string
+		resets   bool
+	}{
+		{sequence: "\x1b[0m", resets: true},
+		{sequence: "\x1b[m", resets: true},
+		{sequence: "\x1b[31;0m", resets: true},
+		{sequence: "\x1b[31m", resets: false},
+		{sequence: "\x1b[38;5;0m", resets: false},
+		{sequence: "\x1b[48;2;0;0;0m", resets: false},
+		{sequence: "\x1b[38;5;0;0m", resets: true},
+	}
+
+	for _, testCase := range tests {
+		parameters, ok := sgrParameters(testCase.sequence)
+		assert.True(t, ok)
+		assert.Equal(t, testCase.resets, resetsGraphics(parameters), testCase.sequence)
+	}
+
+	_, ok := sgrParameters("\x1b[2K")
+	assert.False(t, ok)
+}
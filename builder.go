@@ -1,33 +1,61 @@
 package marker
 
-import (
-	"github.com/fatih/color"
-)
-
 // MarkBuilder is a better and neater way to mark different patterns of the string
 type MarkBuilder struct {
-	str string
+	str           string
+	rules         []MarkRule
+	overlapPolicy OverlapPolicy
+	renderer      Renderer
 }
 
-// SetString sets the first parameter as the string that is going to be marked
+// SetString sets the first parameter as the string that is going to be marked and clears the previous marks
 func (m *MarkBuilder) SetString(str string) *MarkBuilder {
 	m.str = str
+	m.rules = nil
+	return m
+}
+
+// SetOverlapPolicy sets the policy applied when spans of different marks overlap
+func (m *MarkBuilder) SetOverlapPolicy(policy OverlapPolicy) *MarkBuilder {
+	m.overlapPolicy = policy
 	return m
 }
 
-// Mark marks the string with the matcher function and color
-func (m *MarkBuilder) Mark(matcherFunc MatcherFunc, c *color.Color) *MarkBuilder {
-	m.str = Mark(m.str, matcherFunc, c)
+// SetRenderer sets the Renderer that Build renders the marked string with, ANSIRenderer by default
+func (m *MarkBuilder) SetRenderer(renderer Renderer) *MarkBuilder {
+	m.renderer = renderer
+	return m
+}
+
+// Mark marks the string with the matcher and style
+func (m *MarkBuilder) Mark(matcher Matcher, style Style) *MarkBuilder {
+	return m.AddRule(MarkRule{Matcher: matcher, Style: style})
+}
+
+// MarkFunc marks the string with the matcher and the styles chosen by StyleFunc
+func (m *MarkBuilder) MarkFunc(matcher Matcher, styleFunc StyleFunc) *MarkBuilder {
+	return m.AddRule(MarkRule{Matcher: matcher, StyleFunc: styleFunc})
+}
+
+// MarkMany marks the string with a variable number of matchers and style
+func (m *MarkBuilder) MarkMany(style Style, matchers ...Matcher) *MarkBuilder {
+	for _, matcher := range matchers {
+		m.Mark(matcher, style)
+	}
 	return m
 }
 
-// MarkMany marks the string with a variable number of matcher functions and color
-func (m *MarkBuilder) MarkMany(c *color.Color, matcherFuncs ...MatcherFunc) *MarkBuilder {
-	m.str = MarkMany(m.str, c, matcherFuncs...)
+// AddRule marks the string with the given rule
+func (m *MarkBuilder) AddRule(rule MarkRule) *MarkBuilder {
+	m.rules = append(m.rules, rule)
 	return m
 }
 
 // Build returns the marked string
 func (m *MarkBuilder) Build() string {
-	return m.str
+	marked := markSegmentsOf(m.str, m.rules, m.overlapPolicy)
+	if m.renderer == nil {
+		return ANSIRenderer{}.Render(marked)
+	}
+	return m.renderer.Render(marked)
 }
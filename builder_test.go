@@ -20,8 +20,8 @@ func Test_Builder(t *testing.T) {
 	b := MarkBuilder{}
 
 	actualString := b.SetString("Skydome is a data company.").
-		Mark(MatchAll("Skydome"), blueFg).
-		MarkMany(redFg, MatchAll("data"), MatchAll("company")).
+		Mark(MatchAll("Skydome"), FromColor(blueFg)).
+		MarkMany(FromColor(redFg), MatchAll("data"), MatchAll("company")).
 		Build()
 
 	expectedString := fmt.Sprintf("%s is a %s %s.", blue("Skydome"), red("data"), red("company"))
@@ -43,7 +43,42 @@ func Benchmark_Builder(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		builder.SetString("Skydome is a data company.").
-			Mark(skydomeMatcher, blueFg).
+			Mark(skydomeMatcher, FromColor(blueFg)).
 			Build()
 	}
 }
+
+func Test_BuilderOverlapPolicy(t *testing.T) {
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+
+	b := MarkBuilder{}
+
+	actualString := b.SetString("mail john@example.com for an example").
+		SetOverlapPolicy(OverlapLongest).
+		Mark(MatchAll("example"), FromColor(redFg)).
+		Mark(MatchEmail(), FromColor(blueFg)).
+		Build()
+
+	expectedString := fmt.Sprintf("mail %s for an %s", blue("john@example.com"), red("example"))
+	assert.Equal(t, expectedString, actualString)
+
+	actualString = b.SetString("example").Build()
+	assert.Equal(t, "example", actualString)
+}
+
+func Test_BuilderRenderer(t *testing.T) {
+	b := MarkBuilder{}
+
+	actualString := b.SetString("Skydome is a data company.").
+		SetRenderer(PlainRenderer{}).
+		Mark(MatchAll("Skydome"), Fg(Blue)).
+		Build()
+
+	assert.Equal(t, "Skydome is a data company.", actualString)
+}
@@ -0,0 +1,281 @@
+// Command marker colorizes the lines read from stdin with the given rules and writes them to stdout.
+//
+//	go test ./... | marker -e 'FAIL=bold red' -e 'PASS=green' --surrounded '(' ')=faint'
+//	marker -c rules.yaml -f app.log
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cyucelen/marker"
+)
+
+const usage = `Usage: marker [options] [rules]
+
+Reads lines from stdin, or follows a file with --follow, and writes them to stdout, marked with the rules.
+
+Rules, where VALUE=STYLE marks with STYLE and VALUE alone marks with the current style:
+  -e, --text TEXT[=STYLE]            mark all occurrences of TEXT
+  -r, --regexp PATTERN[=STYLE]       mark the matches of the regular expression
+      --surrounded OPEN CLOSE[=STYLE]
+                                     mark the text between OPEN and CLOSE
+  -t, --timestamp LAYOUT[=STYLE]     mark timestamps in LAYOUT, such as RFC3339 or Kitchen
+      --email[=STYLE]                mark email addresses
+      --days[=STYLE]                 mark the days of the week
+  -c, --config FILE                  add the rules in a YAML, JSON or TOML rule file
+  -p, --preset NAME                  add the rules of a built-in preset: %s
+
+Options:
+  -f, --follow FILE                  follow FILE like tail -F instead of reading stdin
+  -n, --lines N                      start following from the last N lines of FILE (default 0)
+  -s, --style STYLE                  style of the following rules without their own style (default "bold")
+      --color MODE                   auto, always or never (default "auto")
+  -h, --help                         show this help
+
+Styles are attributes and colors such as "bold red on white", "underline #ff8700" or "on 236".
+Numbers alone after = are part of VALUE, as in exit=0, so a 256-color index needs a word along with it.
+`
+
+// config is the configuration of marker read from the command line
+type config struct {
+	rules     []marker.MarkRule
+	colorMode marker.ColorMode
+	follow    string
+	lines     int
+	help      bool
+}
+
+func main() {
+	cfg, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "marker: %v\n\n", err)
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	if cfg.help {
+		printUsage(os.Stdout)
+		return
+	}
+
+	writeMarker := marker.NewWriteMarker(os.Stdout, marker.WithColorMode(cfg.colorMode))
+	writeMarker.AddRules(cfg.rules)
+	if cfg.follow != "" {
+		err = marker.Follow(context.Background(), cfg.follow, writeMarker, marker.WithLastLines(cfg.lines))
+	} else {
+		err = run(os.Stdin, writeMarker)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "marker: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, usage, strings.Join(marker.PresetNames(), ", "))
+}
+
+// run writes the lines read from in to out one by one, so that each line is marked as soon as it is read
+func run(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if _, writeErr := io.WriteString(out, line); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parseArgs reads the rules and options in given arguments. Flags are parsed by hand because --surrounded
+// takes two values, which the flag package does not support.
+func parseArgs(args []string) (config, error) {
+	cfg := config{}
+	style := marker.TextStyle{Attrs: marker.Bold}
+
+	for i := 0; i < len(args); i++ {
+		flag := args[i]
+		name, value, hasValue := splitFlag(flag)
+		// next returns the value of the flag, either given with = or as the next argument
+		next := func() (string, error) {
+			if hasValue {
+				hasValue = false
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag %s needs a value", flag)
+			}
+			i++
+			return args[i], nil
+		}
+
+		var rule marker.MarkRule
+		var err error
+		switch name {
+		case "h", "help":
+			cfg.help = true
+			continue
+		case "s", "style":
+			var spec string
+			if spec, err = next(); err == nil {
+				style, err = marker.ParseStyle(spec)
+			}
+		case "color":
+			var mode string
+			if mode, err = next(); err == nil {
+				cfg.colorMode, err = parseColorMode(mode)
+			}
+		case "f", "follow":
+			cfg.follow, err = next()
+		case "n", "lines":
+			var lines string
+			if lines, err = next(); err == nil {
+				cfg.lines, err = strconv.Atoi(lines)
+				if err == nil && cfg.lines < 0 {
+					err = fmt.Errorf("number of lines must not be negative")
+				}
+			}
+		case "c", "config":
+			var path string
+			if path, err = next(); err == nil {
+				var rules []marker.MarkRule
+				rules, err = marker.LoadRulesFile(path)
+				cfg.rules = append(cfg.rules, rules...)
+			}
+			if err != nil {
+				return config{}, err
+			}
+			continue
+		case "p", "preset":
+			var name string
+			if name, err = next(); err == nil {
+				var rules []marker.MarkRule
+				rules, err = marker.Preset(name)
+				cfg.rules = append(cfg.rules, rules...)
+			}
+			if err != nil {
+				return config{}, fmt.Errorf("%s: %v", flag, err)
+			}
+			continue
+		case "e", "text":
+			var text string
+			if text, err = next(); err == nil {
+				text, rule.Style = splitStyle(text, style)
+				rule.Matcher = marker.MatchAll(text)
+			}
+		case "r", "regexp":
+			var pattern string
+			if pattern, err = next(); err == nil {
+				var r *regexp.Regexp
+				pattern, rule.Style = splitStyle(pattern, style)
+				if r, err = regexp.Compile(pattern); err == nil {
+					rule.Matcher = marker.MatchRegexp(r)
+				}
+			}
+		case "surrounded":
+			var opening, closure string
+			if opening, err = next(); err == nil {
+				hasValue = false
+				if closure, err = next(); err == nil {
+					closure, rule.Style = splitStyle(closure, style)
+					rule.Matcher = marker.MatchSurrounded(opening, closure)
+					if opening == "" || closure == "" {
+						err = fmt.Errorf("OPEN and CLOSE must not be empty")
+					}
+				}
+			}
+		case "t", "timestamp":
+			var layout string
+			if layout, err = next(); err == nil {
+				layout, rule.Style = splitStyle(layout, style)
+				if layout, err = marker.ParseTimestampLayout(layout); err == nil {
+					rule.Matcher = marker.MatchTimestamp(layout)
+				}
+			}
+		case "email", "days":
+			rule.Style = style
+			if hasValue {
+				rule.Style, err = marker.ParseStyle(value)
+			}
+			rule.Matcher = marker.MatchEmail()
+			if name == "days" {
+				rule.Matcher = marker.MatchDaysOfWeek()
+			}
+		case "":
+			return config{}, fmt.Errorf("unexpected argument %q", flag)
+		default:
+			return config{}, fmt.Errorf("unknown flag %s", flag)
+		}
+
+		if err != nil {
+			return config{}, fmt.Errorf("%s: %v", flag, err)
+		}
+		if rule.Matcher != nil {
+			cfg.rules = append(cfg.rules, rule)
+		}
+	}
+	return cfg, nil
+}
+
+// splitFlag splits an argument such as --name=value into the name of the flag and its value
+func splitFlag(arg string) (string, string, bool) {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if name == arg {
+		return "", "", false
+	}
+	if equals := strings.Index(name, "="); equals >= 0 {
+		return name[:equals], name[equals+1:], true
+	}
+	return name, "", false
+}
+
+// splitStyle splits a rule value such as ERROR=red into the value and its style.
+// The value is kept whole with the default style if the part after the last = is not a valid style, or has only
+// 256-color indexes, so that values such as id=\d+ and exit=0 can be given without a style.
+func splitStyle(value string, defaultStyle marker.TextStyle) (string, marker.TextStyle) {
+	equals := strings.LastIndex(value, "=")
+	if equals < 0 || !namesStyle(value[equals+1:]) {
+		return value, defaultStyle
+	}
+	style, err := marker.ParseStyle(value[equals+1:])
+	if err != nil {
+		return value, defaultStyle
+	}
+	return value[:equals], style
+}
+
+// namesStyle reports whether given style spec has a word other than a number, such as a color name, an attribute,
+// a #hex color or on. Numbers alone are more likely to be the value of a key than a color index.
+func namesStyle(spec string) bool {
+	for _, word := range strings.Fields(spec) {
+		if strings.Trim(word, "0123456789") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseColorMode(mode string) (marker.ColorMode, error) {
+	switch mode {
+	case "auto":
+		return marker.ColorAuto, nil
+	case "always":
+		return marker.ColorAlways, nil
+	case "never":
+		return marker.ColorNever, nil
+	}
+	return marker.ColorAuto, fmt.Errorf("unknown color mode %q", mode)
+}
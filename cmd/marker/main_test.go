@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyucelen/marker"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseArgs(t *testing.T) {
+	cfg, err := parseArgs([]string{"-e", "FAIL=bold red", "--text=PASS", "-s", "green", "-r", `id=\d+`,
+		"--surrounded", "[", "]=blue", "--timestamp", "Kitchen=faint", "--email=underline", "--color", "always"})
+	assert.Nil(t, err)
+	assert.Equal(t, marker.ColorAlways, cfg.colorMode)
+
+	styles := []marker.Style{
+		marker.Fg(marker.Red).With(marker.Bold),
+		marker.TextStyle{Attrs: marker.Bold},
+		marker.Fg(marker.Green),
+		marker.Fg(marker.Blue),
+		marker.TextStyle{Attrs: marker.Faint},
+		marker.TextStyle{Attrs: marker.Underline},
+	}
+	if assert.Len(t, cfg.rules, len(styles)) {
+		for i, style := range styles {
+			assert.Equal(t, style, cfg.rules[i].Style, "rule %d", i)
+		}
+	}
+
+	str := "FAIL PASS [api] id=42 at 3:04PM by admin@example.com"
+	expected := "\x1b[1;31mFAIL\x1b[0m \x1b[1mPASS\x1b[0m \x1b[34m[api]\x1b[0m \x1b[32mid=42\x1b[0m at \x1b[2m3:04PM\x1b[0m " +
+		"by \x1b[4madmin@example.com\x1b[0m"
+	assert.Equal(t, expected, marker.Render(str, marker.ANSIRenderer{}, cfg.rules...))
+
+	cfg, err = parseArgs([]string{"-e", "exit=0", "-e", "status=1", "-r", `code=\d+`, "-e", "level=236 on 17", "-e", "host=on 236"})
+	assert.Nil(t, err)
+	line := "exit=0 status=1 code=42 level=236 host"
+	expected = "\x1b[1mexit=0\x1b[0m \x1b[1mstatus=1\x1b[0m \x1b[1mcode=42\x1b[0m \x1b[38;5;236;48;5;17mlevel\x1b[0m=236 " +
+		"\x1b[48;5;236mhost\x1b[0m"
+	assert.Equal(t, expected, marker.Render(line, marker.ANSIRenderer{}, cfg.rules...))
+
+	cfg, err = parseArgs([]string{"-c", "../../testdata/rules.yaml", "--days"})
+	assert.Nil(t, err)
+	assert.Len(t, cfg.rules, 8)
+
+	cfg, err = parseArgs([]string{"--preset", "go-test", "-p=syslog"})
+	assert.Nil(t, err)
+	assert.Len(t, cfg.rules, 7)
+
+	cfg, err = parseArgs([]string{"-f", "app.log", "--lines=10"})
+	assert.Nil(t, err)
+	assert.Equal(t, "app.log", cfg.follow)
+	assert.Equal(t, 10, cfg.lines)
+
+	cfg, err = parseArgs([]string{"--help"})
+	assert.Nil(t, err)
+	assert.True(t, cfg.help)
+}
+
+func Test_parseArgsErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"-e"}, expected: "-e: flag -e needs a value"},
+		{args: []string{"--surrounded", "["}, expected: "--surrounded: flag --surrounded needs a value"},
+		{args: []string{"--surrounded", "", "]"}, expected: "--surrounded: OPEN and CLOSE must not be empty"},
+		{args: []string{"--surrounded", "[", "=blue"}, expected: "--surrounded: OPEN and CLOSE must not be empty"},
+		{args: []string{"-r", "(=red"}, expected: "-r: error parsing regexp: missing closing ): `(`"},
+		{args: []string{"-t", "RFC9999"}, expected: `-t: unsupported timestamp layout "RFC9999"`},
+		{args: []string{"-s", "redd"}, expected: `-s: invalid style "redd": unknown color "redd"`},
+		{args: []string{"--color", "sometimes"}, expected: `--color: unknown color mode "sometimes"`},
+		{args: []string{"-n", "ten"}, expected: `-n: strconv.Atoi: parsing "ten": invalid syntax`},
+		{args: []string{"-n", "-1"}, expected: "-n: number of lines must not be negative"},
+		{args: []string{"--colour"}, expected: "unknown flag --colour"},
+		{args: []string{"ERROR"}, expected: `unexpected argument "ERROR"`},
+		{args: []string{"-p", "iis"}, expected: `-p: unknown preset "iis", presets are apache, combined, docker, go-log, go-test, kubectl, logfmt, nginx, syslog`},
+		{args: []string{"-c", "missing.yaml"}, expected: "open missing.yaml: no such file or directory"},
+	}
+
+	for _, testCase := range tests {
+		_, err := parseArgs(testCase.args)
+		assert.EqualError(t, err, testCase.expected, strings.Join(testCase.args, " "))
+	}
+}
+
+func Test_run(t *testing.T) {
+	var lines []string
+	out := writerFunc(func(p []byte) (int, error) {
+		lines = append(lines, string(p))
+		return len(p), nil
+	})
+
+	err := run(strings.NewReader("first line\nsecond line\nno newline"), out)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first line\n", "second line\n", "no newline"}, lines)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,225 @@
+package marker
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorDepth is the number of colors that a terminal can show.
+// The zero value is DepthTrueColor, so colors are kept as they are unless a lower depth is set.
+type ColorDepth int
+
+// Color depths from the richest to none
+const (
+	DepthTrueColor ColorDepth = iota
+	Depth256
+	Depth16
+	DepthNone
+)
+
+// DetectColorDepth detects the color depth of the terminal that given writer writes to.
+// Files that are not terminals get DepthNone, while terminals get the depth that TERM and COLORTERM environment
+// variables declare. Other writers are not known to be terminals or not, so they get DepthTrueColor.
+func DetectColorDepth(w io.Writer) ColorDepth {
+	return detectColorDepth(w, os.Getenv)
+}
+
+func detectColorDepth(w io.Writer, getenv func(string) string) ColorDepth {
+	file, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return DepthTrueColor
+	}
+	if !isatty.IsTerminal(file.Fd()) && !isatty.IsCygwinTerminal(file.Fd()) {
+		return DepthNone
+	}
+	return colorDepthOf(getenv("TERM"), getenv("COLORTERM"))
+}
+
+// ColorMode decides whether a WriteMarker writes colors
+type ColorMode int
+
+// Color modes, ColorAuto is the default
+const (
+	// ColorAuto writes colors unless NO_COLOR is set, or the output is not a terminal and neither FORCE_COLOR nor
+	// CLICOLOR_FORCE is set. CLICOLOR=0 turns colors off for terminals too.
+	ColorAuto ColorMode = iota
+	// ColorAlways writes colors to any output
+	ColorAlways
+	// ColorNever writes no colors
+	ColorNever
+)
+
+// colorDepthFor returns the color depth of given writer in given mode.
+// getenv looks up environment variables, which is os.Getenv outside of tests.
+func colorDepthFor(mode ColorMode, w io.Writer, getenv func(string) string) ColorDepth {
+	terminalDepth := colorDepthOf(getenv("TERM"), getenv("COLORTERM"))
+	if terminalDepth == DepthNone {
+		terminalDepth = Depth16
+	}
+
+	switch mode {
+	case ColorNever:
+		return DepthNone
+	case ColorAlways:
+		return terminalDepth
+	}
+
+	if getenv("NO_COLOR") != "" {
+		return DepthNone
+	}
+	switch force := getenv("FORCE_COLOR"); force {
+	case "":
+	case "0", "false":
+		return DepthNone
+	case "1":
+		return Depth16
+	case "2":
+		return Depth256
+	case "3":
+		return DepthTrueColor
+	default:
+		return terminalDepth
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return terminalDepth
+	}
+	if getenv("CLICOLOR") == "0" {
+		return DepthNone
+	}
+	return detectColorDepth(w, getenv)
+}
+
+// colorDepthOf returns the color depth that given values of TERM and COLORTERM declare
+func colorDepthOf(term, colorTerm string) ColorDepth {
+	term = strings.ToLower(term)
+	switch {
+	case term == "dumb":
+		return DepthNone
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return DepthTrueColor
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return DepthTrueColor
+	case strings.Contains(term, "256"):
+		return Depth256
+	}
+	return Depth16
+}
+
+// Degrade returns the nearest color that can be shown with given color depth
+func (c Color) Degrade(depth ColorDepth) Color {
+	switch {
+	case c.kind == colorDefault || depth == DepthTrueColor:
+		return c
+	case depth == DepthNone:
+		return Color{}
+	case depth == Depth256 && c.kind == colorRGB:
+		return Color256(nearest256(c.r, c.g, c.b))
+	case depth == Depth256 || c.kind == colorBasic:
+		return c
+	case c.kind == colorIndexed && c.index < 16:
+		return Color{kind: colorBasic, index: c.index}
+	}
+	r, g, b := c.RGB()
+	return Color{kind: colorBasic, index: nearestBasic(r, g, b)}
+}
+
+// degradeStyle returns given style with its colors degraded to given color depth.
+// Underline colors are dropped below 256 colors as such terminals cannot show them.
+func degradeStyle(style Style, depth ColorDepth) TextStyle {
+	degraded := composeStyles(TextStyle{}, style)
+	degraded.Fg = degraded.Fg.Degrade(depth)
+	degraded.Bg = degraded.Bg.Degrade(depth)
+	degraded.Ul = degraded.Ul.Degrade(depth)
+	if depth >= Depth16 {
+		degraded.Ul = Color{}
+	}
+	return degraded
+}
+
+// degradeSGR rewrites the extended colors of an SGR sequence with given color depth.
+// Other sequences are returned as they are.
+func degradeSGR(sequence string, depth ColorDepth) string {
+	parameters, ok := sgrParameters(sequence)
+	if !ok {
+		return sequence
+	}
+	degraded := make([]string, 0, len(parameters))
+	for i := 0; i < len(parameters); i++ {
+		code, _ := strconv.Atoi(parameters[i])
+		if code != foregroundTarget && code != backgroundTarget && code != underlineTarget {
+			degraded = append(degraded, parameters[i])
+			continue
+		}
+		c, n := extendedColor(parameters[i+1:])
+		if n == 0 {
+			degraded = append(degraded, parameters[i])
+			continue
+		}
+		i += n
+		if code != underlineTarget || depth < Depth16 {
+			degraded = append(degraded, c.Degrade(depth).sgr(code)...)
+		}
+	}
+	if len(degraded) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(degraded, ";") + "m"
+}
+
+// nearest256 returns the index of the color in the color cube or the gray ramp of the 256-color palette
+// that is nearest to given color
+func nearest256(r, g, b uint8) uint8 {
+	cube := 16 + 36*nearestLevel(r) + 6*nearestLevel(g) + nearestLevel(b)
+	gray := uint8(232)
+	if average := (int(r) + int(g) + int(b)) / 3; average > 238 {
+		gray = 255
+	} else if average > 8 {
+		gray = uint8(232 + (average-3)/10)
+	}
+
+	cr, cg, cb := Color256(cube).RGB()
+	gr, gg, gb := Color256(gray).RGB()
+	if distance(r, g, b, gr, gg, gb) < distance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+// nearestLevel returns the index of the intensity of the color cube that is nearest to given intensity
+func nearestLevel(v uint8) uint8 {
+	nearest := uint8(0)
+	for i, level := range cubeLevels {
+		if absDiff(v, level) < absDiff(v, cubeLevels[nearest]) {
+			nearest = uint8(i)
+		}
+	}
+	return nearest
+}
+
+// nearestBasic returns the index of the basic color of DefaultPalette that is nearest to given color
+func nearestBasic(r, g, b uint8) uint8 {
+	nearest, nearestDistance := uint8(0), -1
+	for i := uint8(0); i < 16; i++ {
+		br, bg, bb := Color{kind: colorBasic, index: i}.RGB()
+		if d := distance(r, g, b, br, bg, bb); nearestDistance < 0 || d < nearestDistance {
+			nearest, nearestDistance = i, d
+		}
+	}
+	return nearest
+}
+
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
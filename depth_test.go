@@ -0,0 +1,111 @@
+package marker
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_colorDepthOf(t *testing.T) {
+	tests := []struct {
+		term      string
+		colorTerm string
+		expected  ColorDepth
+	}{
+		{term: "xterm", colorTerm: "truecolor", expected: DepthTrueColor},
+		{term: "xterm-256color", colorTerm: "24bit", expected: DepthTrueColor},
+		{term: "xterm-direct", expected: DepthTrueColor},
+		{term: "xterm-256color", expected: Depth256},
+		{term: "screen-256color", expected: Depth256},
+		{term: "xterm", expected: Depth16},
+		{term: "", expected: Depth16},
+		{term: "dumb", colorTerm: "truecolor", expected: DepthNone},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, colorDepthOf(testCase.term, testCase.colorTerm), testCase.term)
+	}
+}
+
+func Test_DetectColorDepth(t *testing.T) {
+	assert.Equal(t, DepthTrueColor, DetectColorDepth(&bytes.Buffer{}))
+
+	file, err := ioutil.TempFile("", "marker")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	assert.Equal(t, DepthNone, DetectColorDepth(file))
+}
+
+func Test_ColorDegrade(t *testing.T) {
+	tests := []struct {
+		color    Color
+		depth    ColorDepth
+		expected Color
+	}{
+		{color: RGB(255, 135, 0), depth: DepthTrueColor, expected: RGB(255, 135, 0)},
+		{color: RGB(255, 135, 0), depth: Depth256, expected: Color256(208)},
+		{color: RGB(100, 100, 100), depth: Depth256, expected: Color256(241)},
+		{color: RGB(250, 10, 10), depth: Depth16, expected: BrightRed},
+		{color: Color256(208), depth: Depth256, expected: Color256(208)},
+		{color: Color256(4), depth: Depth16, expected: Blue},
+		{color: Color256(22), depth: Depth16, expected: Black},
+		{color: Color256(34), depth: Depth16, expected: Green},
+		{color: Red, depth: Depth16, expected: Red},
+		{color: Red, depth: DepthNone, expected: Color{}},
+		{color: Color{}, depth: Depth16, expected: Color{}},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, testCase.color.Degrade(testCase.depth), testCase.color.String())
+	}
+}
+
+func Test_ANSIRendererDepth(t *testing.T) {
+	rules := []MarkRule{{Matcher: MatchAll("ERROR"), Style: Fg(RGB(255, 135, 0)).UnderlinedIn(Color256(196))}}
+	str := "\x1b[48;2;0;0;238;58;5;1mERROR\x1b[0m"
+
+	assert.Equal(t, "\x1b[48;2;0;0;238;58;5;1m\x1b[4;38;2;255;135;0;58;5;196mERROR\x1b[0m\x1b[48;2;0;0;238;58;5;1m\x1b[0m", Render(str, ANSIRenderer{}, rules...))
+	assert.Equal(t, "\x1b[48;5;21;58;5;1m\x1b[4;38;5;208;58;5;196mERROR\x1b[0m\x1b[48;5;21;58;5;1m\x1b[0m", Render(str, ANSIRenderer{Depth: Depth256}, rules...))
+	assert.Equal(t, "\x1b[44m\x1b[4;33mERROR\x1b[0m\x1b[44m\x1b[0m", Render(str, ANSIRenderer{Depth: Depth16}, rules...))
+	assert.Equal(t, "ERROR", Render(str, ANSIRenderer{Depth: DepthNone}, rules...))
+}
+
+func Test_colorDepthFor(t *testing.T) {
+	file, err := ioutil.TempFile("", "marker")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	tests := []struct {
+		mode     ColorMode
+		writer   io.Writer
+		env      map[string]string
+		expected ColorDepth
+	}{
+		{mode: ColorAuto, writer: file, expected: DepthNone},
+		{mode: ColorAuto, writer: &bytes.Buffer{}, expected: DepthTrueColor},
+		{mode: ColorAuto, writer: &bytes.Buffer{}, env: map[string]string{"NO_COLOR": "1"}, expected: DepthNone},
+		{mode: ColorAuto, writer: file, env: map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, expected: DepthNone},
+		{mode: ColorAuto, writer: file, env: map[string]string{"FORCE_COLOR": "1"}, expected: Depth16},
+		{mode: ColorAuto, writer: file, env: map[string]string{"FORCE_COLOR": "2"}, expected: Depth256},
+		{mode: ColorAuto, writer: file, env: map[string]string{"FORCE_COLOR": "3"}, expected: DepthTrueColor},
+		{mode: ColorAuto, writer: file, env: map[string]string{"FORCE_COLOR": "true", "TERM": "xterm-256color"}, expected: Depth256},
+		{mode: ColorAuto, writer: &bytes.Buffer{}, env: map[string]string{"FORCE_COLOR": "0"}, expected: DepthNone},
+		{mode: ColorAuto, writer: file, env: map[string]string{"CLICOLOR_FORCE": "1", "TERM": "dumb"}, expected: Depth16},
+		{mode: ColorAuto, writer: file, env: map[string]string{"CLICOLOR_FORCE": "0"}, expected: DepthNone},
+		{mode: ColorAuto, writer: &bytes.Buffer{}, env: map[string]string{"CLICOLOR": "0"}, expected: DepthNone},
+		{mode: ColorAlways, writer: file, env: map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, expected: DepthTrueColor},
+		{mode: ColorAlways, writer: file, expected: Depth16},
+		{mode: ColorNever, writer: &bytes.Buffer{}, env: map[string]string{"FORCE_COLOR": "3"}, expected: DepthNone},
+	}
+
+	for i, testCase := range tests {
+		getenv := func(key string) string { return testCase.env[key] }
+		assert.Equal(t, testCase.expected, colorDepthFor(testCase.mode, testCase.writer, getenv), "test case %d", i)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"regexp"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 func main() {
@@ -14,9 +13,9 @@ func main() {
 	r, _ := regexp.Compile("([a-z]?cream)")
 
 	markedWithBuilder := b.SetString(rhyme).
-		Mark(marker.MatchN("for ice", 1), color.New(color.FgRed)).
-		Mark(marker.MatchAll("all"), color.New(color.FgMagenta)).
-		Mark(marker.MatchRegexp(r), color.New(color.FgYellow)).
+		Mark(marker.MatchN("for ice", 1), marker.Fg(marker.Red)).
+		Mark(marker.MatchAll("all"), marker.Fg(marker.Magenta)).
+		Mark(marker.MatchRegexp(r), marker.Fg(marker.Yellow)).
 		Build()
 
 	fmt.Println(markedWithBuilder)
@@ -5,22 +5,21 @@ import (
 	"strings"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
-func MatchFirst(pattern string) marker.MatcherFunc {
-	return func(str string) marker.Match {
-		return marker.Match{
-			// replace first matching pattern with %s
-			Template: strings.Replace(str, pattern, "%s", 1),
-			// patterns to be colorized by Mark, in order
-			Patterns: []string{pattern},
+func MatchFirst(pattern string) marker.SpanMatcherFunc {
+	return func(str string) []marker.Span {
+		start := strings.Index(str, pattern)
+		if start < 0 {
+			return nil
 		}
+		// byte offsets of the pattern to be colorized by Mark
+		return []marker.Span{{Start: start, End: start + len(pattern), Text: pattern}}
 	}
 }
 
 func main() {
 	boringLog := "[INFO] Nobody wants to read pale [INFO] tags."
-	brilliantLog := marker.Mark(boringLog, MatchFirst("[INFO]"), color.New(color.FgBlue))
+	brilliantLog := marker.Mark(boringLog, MatchFirst("[INFO]"), marker.Fg(marker.Blue))
 	fmt.Println(brilliantLog)
 }
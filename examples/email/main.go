@@ -4,11 +4,10 @@ import (
 	"fmt"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 func main() {
 	message := "This is a message by dev@gmail.com and john@doe.io"
-	brilliantLog := marker.Mark(message, marker.MatchEmail(), color.New(color.FgRed))
+	brilliantLog := marker.Mark(message, marker.MatchEmail(), marker.Fg(marker.Red))
 	fmt.Println(brilliantLog)
 }
@@ -4,20 +4,19 @@ import (
 	"log"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 var (
-	redFg  = color.New(color.FgRed)
-	blueFg = color.New(color.FgBlue)
+	redFg  = marker.Fg(marker.Red)
+	blueFg = marker.Fg(marker.Blue)
 )
 
 func main() {
 	stdoutMarker := marker.NewStdoutMarker()
 
 	markRules := []marker.MarkRule{
-		{marker.MatchBracketSurrounded(), blueFg},
-		{marker.MatchAll("marker"), redFg},
+		{Matcher: marker.MatchBracketSurrounded(), Style: blueFg},
+		{Matcher: marker.MatchAll("marker"), Style: redFg},
 	}
 
 	stdoutMarker.AddRules(markRules)
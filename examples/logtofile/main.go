@@ -9,12 +9,11 @@ import (
 	"os/exec"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 var (
-	magentaFg = color.New(color.FgMagenta)
-	blueFg    = color.New(color.FgBlue)
+	magentaFg = marker.Fg(marker.Magenta)
+	blueFg    = marker.Fg(marker.Blue)
 )
 
 func main() {
@@ -24,8 +23,8 @@ func main() {
 	writeMarker := marker.NewWriteMarker(w)
 
 	markRules := []marker.MarkRule{
-		{marker.MatchBracketSurrounded(), blueFg},
-		{marker.MatchAll("marker"), magentaFg},
+		{Matcher: marker.MatchBracketSurrounded(), Style: blueFg},
+		{Matcher: marker.MatchAll("marker"), Style: magentaFg},
 	}
 
 	writeMarker.AddRules(markRules)
@@ -4,11 +4,10 @@ import (
 	"fmt"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 func main() {
 	aristotleQuote := "The more you know, the more you realize you don't know."
-	emphasized := marker.Mark(aristotleQuote, marker.MatchAll("know"), color.New(color.FgRed))
+	emphasized := marker.Mark(aristotleQuote, marker.MatchAll("know"), marker.Fg(marker.Red))
 	fmt.Println(emphasized)
 }
@@ -4,11 +4,10 @@ import (
 	"fmt"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
 func main() {
 	boringLog := "[INFO] Nobody wants to read pale [INFO] tags."
-	brilliantLog := marker.Mark(boringLog, marker.MatchN("[INFO]", 1), color.New(color.FgBlue))
+	brilliantLog := marker.Mark(boringLog, marker.MatchN("[INFO]", 1), marker.Fg(marker.Blue))
 	fmt.Println(brilliantLog)
 }
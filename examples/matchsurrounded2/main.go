@@ -4,12 +4,11 @@ import (
 	"fmt"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
-var magentaFg = color.New(color.FgMagenta)
-var redFg = color.New(color.FgRed)
-var blueFg = color.New(color.FgBlue)
+var magentaFg = marker.Fg(marker.Magenta)
+var redFg = marker.Fg(marker.Red)
+var blueFg = marker.Fg(marker.Blue)
 
 func main() {
 	sentence := "[INFO] This is what log lines look like"
@@ -5,17 +5,14 @@ import (
 	"regexp"
 
 	"github.com/cyucelen/marker"
-	"github.com/fatih/color"
 )
 
-var magentaFg = color.New(color.FgMagenta)
-var hiMagentaFg = color.New(color.FgHiMagenta)
-var redFg = color.New(color.FgRed)
-var blueFg = color.New(color.FgBlue)
-var blackFg = color.New(color.FgBlack)
-var greenFg = color.New(color.FgGreen)
-var yellowFg = color.New(color.FgYellow)
-var whiteFg = color.New(color.FgWhite)
+var magentaFg = marker.Fg(marker.Magenta)
+var hiMagentaFg = marker.Fg(marker.BrightMagenta)
+var redFg = marker.Fg(marker.Red)
+var blueFg = marker.Fg(marker.Blue)
+var greenFg = marker.Fg(marker.Green)
+var whiteFg = marker.Fg(marker.White)
 
 func main() {
 	rhyme := "I scream, you all scream, we all scream for ice cream."
@@ -26,12 +23,12 @@ func main() {
 
 	rhymeMarkExample = "Mark All \"all\" and \"ice\":\t\t\t\t" + rhyme
 	allMarked = marker.Mark(rhymeMarkExample, marker.MatchAll("all"), greenFg)
-	allIceMarked := marker.Mark(allMarked, marker.MatchAll("ice"), hiMagentaFg.Add(color.BgWhite))
+	allIceMarked := marker.Mark(allMarked, marker.MatchAll("ice"), hiMagentaFg.On(marker.White))
 	fmt.Println(allIceMarked)
 
 	r, _ := regexp.Compile("([a-z]?cream)")
-	markedWithRegexp := marker.Mark(rhyme, marker.MatchRegexp(r), whiteFg.Add(color.BgHiBlue))
-	regexpExampleHeader := fmt.Sprintf("Mark Regexp \"%s\":\t\t\t", whiteFg.Add(color.BgHiBlue).Sprint("([a-z]?cream)"))
+	markedWithRegexp := marker.Mark(rhyme, marker.MatchRegexp(r), whiteFg.On(marker.BrightBlue))
+	regexpExampleHeader := marker.Mark("Mark Regexp \"([a-z]?cream)\":\t\t\t", marker.MatchAll("([a-z]?cream)"), whiteFg.On(marker.BrightBlue))
 	fmt.Println(regexpExampleHeader + markedWithRegexp)
 
 	b := &marker.MarkBuilder{}
@@ -40,7 +37,10 @@ func main() {
 		Mark(marker.MatchAll("all"), magentaFg).
 		Mark(marker.MatchRegexp(r), blueFg).
 		Build()
-	builderExampleHeader := fmt.Sprintf("Mark \"%s\", \"%s\", \"%s\" :\t",
-		color.New(color.FgRed).Sprint("for ice"), magentaFg.Sprint("all"), blueFg.Sprint("([a-z]?cream)"))
+	builderExampleHeader := b.SetString("Mark \"for ice\", \"all\", \"([a-z]?cream)\" :\t").
+		Mark(marker.MatchAll("for ice"), redFg).
+		Mark(marker.MatchAll("all"), magentaFg).
+		Mark(marker.MatchAll("([a-z]?cream)"), blueFg).
+		Build()
 	fmt.Println(builderExampleHeader + markedWithBuilder)
 }
@@ -0,0 +1,209 @@
+package marker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// FollowOption is functional option type for Follow
+type FollowOption func(*follower)
+
+// WithLastLines makes Follow start with the last n lines of the file instead of its end
+func WithLastLines(n int) FollowOption {
+	return func(f *follower) {
+		f.lastLines = n
+	}
+}
+
+// WithPollInterval sets how often Follow checks the file for new lines, 250ms by default
+func WithPollInterval(interval time.Duration) FollowOption {
+	return func(f *follower) {
+		f.pollInterval = interval
+	}
+}
+
+// follower reads the lines appended to a file and keeps reading when the file is truncated or rotated
+type follower struct {
+	path         string
+	out          io.Writer
+	lastLines    int
+	pollInterval time.Duration
+
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	pending []byte
+}
+
+// Follow writes the lines appended to the file at path to out, like tail -F, until ctx is done.
+// Each line is written with a separate call, so out is usually a WriteMarker that marks the lines with its rules.
+// When the file is truncated it is read again from its start, and when it is rotated or does not exist yet
+// the file at path is read as soon as it appears.
+// Follow returns ctx.Err() when ctx is done, or an error if the file cannot be read.
+func Follow(ctx context.Context, path string, out io.Writer, options ...FollowOption) error {
+	f := &follower{path: path, out: out, pollInterval: 250 * time.Millisecond}
+	for _, option := range options {
+		option(f)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	defer f.close()
+
+	if f.file != nil {
+		offset, err := lastLinesOffset(f.file, f.info.Size(), f.lastLines)
+		if err != nil {
+			return err
+		}
+		f.offset = offset
+	}
+
+	ticker := time.NewTicker(f.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.poll(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// open opens the file at path, leaving the follower without a file when it does not exist
+func (f *follower) open() error {
+	f.file, f.info, f.offset = nil, nil, 0
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.info = file, info
+	return nil
+}
+
+func (f *follower) close() {
+	if f.file != nil {
+		f.file.Close()
+	}
+}
+
+// poll writes the new lines of the file, then checks whether it is truncated or rotated
+func (f *follower) poll() error {
+	if f.file == nil {
+		// the file is not created yet, or it is removed again before it is opened
+		if err := f.open(); err != nil || f.file == nil {
+			return err
+		}
+	}
+	if err := f.read(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		// the file is rotated and the new one is not created yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !os.SameFile(info, f.info):
+		if err := f.flush(); err != nil {
+			return err
+		}
+		f.close()
+		if err := f.open(); err != nil || f.file == nil {
+			return err
+		}
+		return f.read()
+	case info.Size() < f.offset:
+		f.offset, f.pending = 0, nil
+		return f.read()
+	}
+	return nil
+}
+
+// read writes the complete lines from the offset to the end of the file, keeping the last incomplete line pending
+func (f *follower) read() error {
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := f.file.ReadAt(buffer, f.offset)
+		f.offset += int64(n)
+		f.pending = append(f.pending, buffer[:n]...)
+		for {
+			newline := bytes.IndexByte(f.pending, '\n')
+			if newline < 0 {
+				break
+			}
+			if _, err := f.out.Write(f.pending[:newline+1]); err != nil {
+				return err
+			}
+			f.pending = f.pending[newline+1:]
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// flush writes the pending incomplete line, when the file it belongs to will not be read anymore
+func (f *follower) flush() error {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	_, err := f.out.Write(f.pending)
+	f.pending = nil
+	return err
+}
+
+// lastLinesOffset returns the offset of the last n lines of a file with given size
+func lastLinesOffset(r io.ReaderAt, size int64, n int) (int64, error) {
+	if n <= 0 {
+		return size, nil
+	}
+
+	buffer := make([]byte, 4096)
+	end := size
+	lines := 0
+	for end > 0 {
+		start := end - int64(len(buffer))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buffer[:end-start]
+		if _, err := r.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			// the newline ending the last line does not start another line
+			if chunk[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+			lines++
+			if lines == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
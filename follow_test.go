@@ -0,0 +1,141 @@
+package marker
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineRecorder records the writes of Follow, which runs on another goroutine
+type lineRecorder struct {
+	sync.Mutex
+	lines []string
+}
+
+func (r *lineRecorder) Write(p []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+	r.lines = append(r.lines, string(p))
+	return len(p), nil
+}
+
+func (r *lineRecorder) waitFor(t *testing.T, expected []string) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.Lock()
+		lines := append([]string(nil), r.lines...)
+		r.Unlock()
+		if len(lines) >= len(expected) || time.Now().After(deadline) {
+			assert.Equal(t, expected, lines)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func appendFile(t *testing.T, path, text string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	assert.Nil(t, err)
+	_, err = file.WriteString(text)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+}
+
+func Test_Follow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "marker")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "first\nsecond\nthird\n")
+
+	out := &lineRecorder{}
+	writeMarker := NewWriteMarker(out, WithColorMode(ColorAlways), WithColorDepth(Depth16))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, writeMarker, WithLastLines(2), WithPollInterval(time.Millisecond))
+	}()
+
+	expected := []string{"second\n", "third\n"}
+	out.waitFor(t, expected)
+
+	appendFile(t, path, "ERROR one\nERROR par")
+	appendFile(t, path, "tial\n")
+	expected = append(expected, "\x1b[31mERROR\x1b[0m one\n", "\x1b[31mERROR\x1b[0m partial\n")
+	out.waitFor(t, expected)
+
+	assert.Nil(t, os.Truncate(path, 0))
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "after truncation\n")
+	expected = append(expected, "after truncation\n")
+	out.waitFor(t, expected)
+
+	assert.Nil(t, os.Rename(path, path+".1"))
+	appendFile(t, path+".1", "before rotation\n")
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "after rotation\n")
+	expected = append(expected, "before rotation\n", "after rotation\n")
+	out.waitFor(t, expected)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func Test_FollowMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "marker")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	out := &lineRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, out, WithLastLines(1), WithPollInterval(time.Millisecond))
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "first\nsecond\n")
+	expected := []string{"first\n", "second\n"}
+	out.waitFor(t, expected)
+
+	assert.Nil(t, os.Remove(path))
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "recreated\n")
+	expected = append(expected, "recreated\n")
+	out.waitFor(t, expected)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func Test_lastLinesOffset(t *testing.T) {
+	tests := []struct {
+		text     string
+		n        int
+		expected int64
+	}{
+		{text: "a\nb\nc\n", n: 0, expected: 6},
+		{text: "a\nb\nc\n", n: 1, expected: 4},
+		{text: "a\nb\nc\n", n: 2, expected: 2},
+		{text: "a\nb\nc\n", n: 5, expected: 0},
+		{text: "a\nb\nc", n: 1, expected: 4},
+		{text: "", n: 3, expected: 0},
+		{text: strings.Repeat("line\n", 2000), n: 1000, expected: 5000},
+	}
+
+	for _, testCase := range tests {
+		offset, err := lastLinesOffset(strings.NewReader(testCase.text), int64(len(testCase.text)), testCase.n)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, offset, "%q %d", testCase.text, testCase.n)
+	}
+}
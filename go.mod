@@ -3,8 +3,11 @@ module github.com/cyucelen/marker
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/fatih/color v1.7.0
 	github.com/mattn/go-colorable v0.1.2 // indirect
-	github.com/mattn/go-isatty v0.0.9 // indirect
+	github.com/mattn/go-isatty v0.0.9
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
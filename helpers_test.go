@@ -0,0 +1,46 @@
+package marker
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// spanTexts returns the texts of the spans that given matcher finds in str
+func spanTexts(t *testing.T, matcher Matcher, str string) []string {
+	var texts []string
+	for _, span := range matcher.Spans(str) {
+		assert.Equal(t, str[span.Start:span.End], span.Text)
+		texts = append(texts, span.Text)
+	}
+	return texts
+}
+
+// styledTexts returns the styles of the marked parts of given string by their text
+func styledTexts(str string, rules []MarkRule) map[string]TextStyle {
+	styles := make(map[string]TextStyle)
+	for _, segment := range markSegmentsOf(str, rules, OverlapSplit).Segments {
+		if style := segment.Style(); style != (TextStyle{}) {
+			styles[segment.Text] = style
+		}
+	}
+	return styles
+}
+
+// unsetColorEnv unsets the environment variables that decide whether colors are written,
+// and returns a function that sets them back
+func unsetColorEnv() func() {
+	values := make(map[string]string)
+	for _, name := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "CLICOLOR"} {
+		if value, ok := os.LookupEnv(name); ok {
+			values[name] = value
+			os.Unsetenv(name)
+		}
+	}
+	return func() {
+		for name, value := range values {
+			os.Setenv(name, value)
+		}
+	}
+}
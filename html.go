@@ -0,0 +1,112 @@
+package marker
+
+import (
+	"html"
+	"strings"
+)
+
+// HTMLRenderer renders marked text as HTML where styled parts of the text become <span> elements.
+// By default the elements get classes such as "marker-fg-red marker-bg-white marker-bold", which are prefixed with
+// ClassPrefix when it is set. With InlineStyle they get a style attribute that uses Palette for the basic colors,
+// or DefaultPalette when Palette is not set.
+// Styles set by the escape sequences of the original text are rendered as well.
+type HTMLRenderer struct {
+	InlineStyle bool
+	ClassPrefix string
+	Palette     *Palette
+}
+
+// Render returns the marked text as HTML
+func (r HTMLRenderer) Render(marked Marked) string {
+	var b strings.Builder
+	for _, run := range marked.runs() {
+		text := html.EscapeString(run.text)
+		if run.style == (TextStyle{}) {
+			b.WriteString(text)
+			continue
+		}
+		if r.InlineStyle {
+			b.WriteString(`<span style="` + r.css(run.style) + `">`)
+		} else {
+			b.WriteString(`<span class="` + r.classes(run.style) + `">`)
+		}
+		b.WriteString(text)
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+func (r HTMLRenderer) classes(style TextStyle) string {
+	prefix := r.ClassPrefix
+	if prefix == "" {
+		prefix = "marker-"
+	}
+	var classes []string
+	if !style.Fg.IsDefault() {
+		classes = append(classes, prefix+"fg-"+className(style.Fg))
+	}
+	if !style.Bg.IsDefault() {
+		classes = append(classes, prefix+"bg-"+className(style.Bg))
+	}
+	if !style.Ul.IsDefault() {
+		classes = append(classes, prefix+"ul-"+className(style.Ul))
+	}
+	for _, name := range style.Attrs.Names() {
+		classes = append(classes, prefix+name)
+	}
+	return strings.Join(classes, " ")
+}
+
+// className returns the name of the color usable in a class name, such as "red", "208" or "ff8700"
+func className(c Color) string {
+	return strings.TrimPrefix(c.String(), "#")
+}
+
+func (r HTMLRenderer) css(style TextStyle) string {
+	palette := DefaultPalette
+	if r.Palette != nil {
+		palette = *r.Palette
+	}
+
+	fg, bg := style.Fg.Hex(palette), style.Bg.Hex(palette)
+	if style.Attrs&ReverseVideo != 0 {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "inherit"
+		}
+	}
+
+	var properties []string
+	if fg != "" {
+		properties = append(properties, "color: "+fg)
+	}
+	if bg != "" {
+		properties = append(properties, "background-color: "+bg)
+	}
+	if style.Attrs&Bold != 0 {
+		properties = append(properties, "font-weight: bold")
+	}
+	if style.Attrs&Faint != 0 {
+		properties = append(properties, "opacity: 0.5")
+	}
+	if style.Attrs&Italic != 0 {
+		properties = append(properties, "font-style: italic")
+	}
+	var decorations []string
+	if style.Attrs&Underline != 0 {
+		decorations = append(decorations, "underline")
+	}
+	if style.Attrs&CrossedOut != 0 {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		properties = append(properties, "text-decoration: "+strings.Join(decorations, " "))
+	}
+	if ul := style.Ul.Hex(palette); ul != "" && style.Attrs&Underline != 0 {
+		properties = append(properties, "text-decoration-color: "+ul)
+	}
+	if style.Attrs&Concealed != 0 {
+		properties = append(properties, "visibility: hidden")
+	}
+	return strings.Join(properties, "; ")
+}
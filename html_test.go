@@ -0,0 +1,42 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTMLRenderer(t *testing.T) {
+	str := "[ERROR] disk <sda> is full"
+
+	classes := Render(str, HTMLRenderer{}, renderTestRules...)
+	expected := `<span class="marker-fg-blue">[</span>` +
+		`<span class="marker-fg-red marker-bold">ERROR</span>` +
+		`<span class="marker-fg-blue">]</span> disk &lt;sda&gt; is full`
+	assert.Equal(t, expected, classes)
+
+	prefixed := Render(str, HTMLRenderer{ClassPrefix: "log-"}, MarkRule{Matcher: MatchAll("full"), Style: Bg(BrightWhite)})
+	assert.Equal(t, `[ERROR] disk &lt;sda&gt; is <span class="log-bg-bright-white">full</span>`, prefixed)
+
+	inline := Render(str, HTMLRenderer{InlineStyle: true}, renderTestRules...)
+	expected = `<span style="color: #0000ee">[</span>` +
+		`<span style="color: #cd0000; font-weight: bold">ERROR</span>` +
+		`<span style="color: #0000ee">]</span> disk &lt;sda&gt; is full`
+	assert.Equal(t, expected, inline)
+
+	palette := DefaultPalette
+	palette[1] = "#ff5555"
+	inline = Render("\x1b[4mERROR\x1b[0m", HTMLRenderer{InlineStyle: true, Palette: &palette}, renderTestRules...)
+	assert.Equal(t, `<span style="color: #ff5555; font-weight: bold; text-decoration: underline">ERROR</span>`, inline)
+
+	orange := Fg(RGB(255, 135, 0)).On(Color256(236)).UnderlinedIn(Red)
+	truecolor := Render("full", HTMLRenderer{}, MarkRule{Matcher: MatchAll("full"), Style: orange})
+	assert.Equal(t, `<span class="marker-fg-ff8700 marker-bg-236 marker-ul-red marker-underline">full</span>`, truecolor)
+
+	truecolor = Render("full", HTMLRenderer{InlineStyle: true}, MarkRule{Matcher: MatchAll("full"), Style: orange})
+	expected = `<span style="color: #ff8700; background-color: #303030; text-decoration: underline; text-decoration-color: #cd0000">full</span>`
+	assert.Equal(t, expected, truecolor)
+
+	reversed := Render("full", HTMLRenderer{InlineStyle: true}, MarkRule{Matcher: MatchAll("full"), Style: Fg(Red).With(ReverseVideo)})
+	assert.Equal(t, `<span style="color: inherit; background-color: #cd0000">full</span>`, reversed)
+}
@@ -0,0 +1,64 @@
+package marker
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	uuidRegexp   = regexp.MustCompile(`[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}`)
+	gitSHARegexp = regexp.MustCompile(`[0-9a-f]{7,40}`)
+	sha1Regexp   = regexp.MustCompile(`[0-9A-Fa-f]{40}`)
+	sha256Regexp = regexp.MustCompile(`[0-9A-Fa-f]{64}`)
+	ulidRegexp   = regexp.MustCompile(`[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}`)
+)
+
+// MatchUUID creates a SpanMatcherFunc that matches UUIDs in their canonical form such as
+// 123e4567-e89b-12d3-a456-426614174000. When versions are given, only the UUIDs of those versions are matched.
+func MatchUUID(versions ...int) SpanMatcherFunc {
+	return matchTokens(uuidRegexp, "-", "-", func(token string) string {
+		if len(versions) == 0 {
+			return token
+		}
+		for _, version := range versions {
+			// the version is the first digit of the third group
+			if strings.EqualFold(token[14:15], strconv.FormatInt(int64(version), 16)) {
+				return token
+			}
+		}
+		return ""
+	})
+}
+
+// MatchGitSHA creates a SpanMatcherFunc that matches short and full git commit hashes, which are 7 to 40 lowercase
+// hex digits. Hashes must have both digits and letters, so that numbers and words such as "decade" are not matched.
+func MatchGitSHA() SpanMatcherFunc {
+	return matchTokens(gitSHARegexp, "-", "-", func(token string) string {
+		if strings.Trim(token, "0123456789") == "" || strings.Trim(token, "abcdef") == "" {
+			return ""
+		}
+		return token
+	})
+}
+
+// MatchSHA1 creates a SpanMatcherFunc that matches SHA-1 digests in hex, which are 40 hex digits
+func MatchSHA1() SpanMatcherFunc {
+	return matchTokens(sha1Regexp, "-", "-", identity)
+}
+
+// MatchSHA256 creates a SpanMatcherFunc that matches SHA-256 digests in hex, which are 64 hex digits,
+// such as the digests of container images after sha256:
+func MatchSHA256() SpanMatcherFunc {
+	return matchTokens(sha256Regexp, "-", "-", identity)
+}
+
+// MatchULID creates a SpanMatcherFunc that matches ULIDs, which are 26 characters of Crockford's base32
+// such as 01ARZ3NDEKTSV4RRFFQ69G5FAV
+func MatchULID() SpanMatcherFunc {
+	return matchTokens(ulidRegexp, "-", "-", identity)
+}
+
+func identity(token string) string {
+	return token
+}
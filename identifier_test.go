@@ -0,0 +1,40 @@
+package marker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatchUUID(t *testing.T) {
+	str := "request 123e4567-e89b-12d3-a456-426614174000 retried as 9F1B2C3D-4E5F-4A6B-8C7D-0E1F2A3B4C5D, " +
+		"not x123e4567-e89b-12d3-a456-426614174000 or 123e4567-e89b-12d3-a456-426614174000-1 or 123e4567-e89b-12d3-a456"
+	assert.Equal(t, []string{"123e4567-e89b-12d3-a456-426614174000", "9F1B2C3D-4E5F-4A6B-8C7D-0E1F2A3B4C5D"},
+		spanTexts(t, MatchUUID(), str))
+	assert.Equal(t, []string{"9F1B2C3D-4E5F-4A6B-8C7D-0E1F2A3B4C5D"}, spanTexts(t, MatchUUID(4), str))
+	assert.Equal(t, []string{"123e4567-e89b-12d3-a456-426614174000", "9F1B2C3D-4E5F-4A6B-8C7D-0E1F2A3B4C5D"},
+		spanTexts(t, MatchUUID(1, 4), str))
+	assert.Nil(t, spanTexts(t, MatchUUID(7), str))
+}
+
+func Test_MatchGitSHA(t *testing.T) {
+	full := "e83c5163316f89bfbde7d9ab23ca2e25604af290"
+	str := "merge 3aee3da into " + full + " (was 8b32a74c), not 1234567, decade, facade7x, 0xabc1234, " +
+		"abc123 or 123e4567-e89b-12d3-a456-426614174000"
+	assert.Equal(t, []string{"3aee3da", full, "8b32a74c"}, spanTexts(t, MatchGitSHA(), str))
+}
+
+func Test_MatchSHA(t *testing.T) {
+	sha1 := "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+	sha256 := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	str := "sha1 " + sha1 + " image@sha256:" + sha256 + " not " + sha1 + "00 or " + strings.ToUpper(sha256) + "x"
+
+	assert.Equal(t, []string{sha1}, spanTexts(t, MatchSHA1(), str))
+	assert.Equal(t, []string{sha256}, spanTexts(t, MatchSHA256(), str))
+}
+
+func Test_MatchULID(t *testing.T) {
+	str := "event 01ARZ3NDEKTSV4RRFFQ69G5FAV stored, not 81ARZ3NDEKTSV4RRFFQ69G5FAV, 01ARZ3NDEKTSV4RRFFQ69G5FAVX or 01ARZ3NDEKTSV4RRFFQ69G5FAI"
+	assert.Equal(t, []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"}, spanTexts(t, MatchULID(), str))
+}
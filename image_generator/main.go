@@ -0,0 +1,68 @@
+// Command image_generator runs all the main packages under examples and saves their outputs as SVG images
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cyucelen/marker"
+)
+
+func main() {
+	inputDir := flag.String("input_dir", "../examples", "directory to Go packages")
+	outputDir := flag.String("output_dir", "../assets/svg", "directory where images will be saved")
+	columns := flag.Int("columns", 0, "minimum width of images in terminal columns")
+	flag.Parse()
+
+	buildDir, err := ioutil.TempDir("", "marker-examples")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(buildDir)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	examples, err := ioutil.ReadDir(*inputDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	renderer := marker.SVGRenderer{Columns: *columns}
+	for _, example := range examples {
+		if !example.IsDir() {
+			continue
+		}
+		srcDir := filepath.Join(*inputDir, example.Name())
+		imagePath := filepath.Join(*outputDir, example.Name()+".svg")
+		fmt.Printf("Generate image %s using go package: %s\n", imagePath, srcDir)
+
+		output, err := runExample(srcDir, filepath.Join(buildDir, example.Name()))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(imagePath, []byte(marker.Render(output, renderer)), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func runExample(srcDir, binary string) (string, error) {
+	build := exec.Command("go", "build", "-o", binary, "./"+srcDir)
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		return "", fmt.Errorf("building %s: %v", srcDir, err)
+	}
+	example := exec.Command(binary)
+	example.Env = append(os.Environ(), "NO_COLOR=", "FORCE_COLOR=3")
+	output, err := example.Output()
+	if err != nil {
+		return "", fmt.Errorf("running %s: %v", srcDir, err)
+	}
+	return string(output), nil
+}
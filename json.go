@@ -0,0 +1,55 @@
+package marker
+
+import (
+	"encoding/json"
+)
+
+// JSONRenderer renders the styled segments of marked text as a JSON list, for editors and other tools
+// that draw the styles themselves. Offsets are byte offsets of the visible text.
+// Each list is followed by a newline, so rendering a stream of lines produces JSON lines.
+type JSONRenderer struct{}
+
+type jsonSegment struct {
+	Start      int               `json:"start"`
+	End        int               `json:"end"`
+	Text       string            `json:"text"`
+	Foreground string            `json:"foreground,omitempty"`
+	Background string            `json:"background,omitempty"`
+	Underline  string            `json:"underline,omitempty"`
+	Attributes []string          `json:"attributes,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+}
+
+// Render returns the segments of marked text as JSON
+func (JSONRenderer) Render(marked Marked) string {
+	segments := make([]jsonSegment, 0, len(marked.Segments))
+	for _, segment := range marked.Segments {
+		style := segment.Style()
+		segments = append(segments, jsonSegment{
+			Start:      segment.Start,
+			End:        segment.End,
+			Text:       segment.Text,
+			Foreground: style.Fg.String(),
+			Background: style.Bg.String(),
+			Underline:  style.Ul.String(),
+			Attributes: style.Attrs.Names(),
+			Meta:       segmentMeta(segment),
+		})
+	}
+	encoded, _ := json.Marshal(segments)
+	return string(encoded) + "\n"
+}
+
+// segmentMeta merges the metadata of the spans covering the segment, inner spans override outer ones
+func segmentMeta(segment Segment) map[string]string {
+	var meta map[string]string
+	for _, span := range segment.Spans {
+		for key, value := range span.Meta {
+			if meta == nil {
+				meta = make(map[string]string)
+			}
+			meta[key] = value
+		}
+	}
+	return meta
+}
@@ -0,0 +1,25 @@
+package marker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JSONRenderer(t *testing.T) {
+	rendered := Render("[ERROR] disk is full", JSONRenderer{}, renderTestRules...)
+	expected := `[{"start":0,"end":1,"text":"[","foreground":"blue"},` +
+		`{"start":1,"end":6,"text":"ERROR","foreground":"red","attributes":["bold"]},` +
+		`{"start":6,"end":7,"text":"]","foreground":"blue"}]` + "\n"
+	assert.Equal(t, expected, rendered)
+
+	r := regexp.MustCompile(`(?P<key>\w+)=(?P<value>\w+)`)
+	groups := MatchRegexpGroups(r, map[string]Style{"key": Fg(Cyan)})
+	rendered = Render("level=warn", JSONRenderer{}, MarkRule{Matcher: groups})
+	expected = `[{"start":0,"end":5,"text":"level","foreground":"cyan","meta":{"group":"key"}},` +
+		`{"start":5,"end":10,"text":"=warn"}]` + "\n"
+	assert.Equal(t, expected, rendered)
+
+	assert.Equal(t, "[]\n", Render("nothing here", JSONRenderer{}, renderTestRules...))
+}
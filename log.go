@@ -3,34 +3,80 @@ package marker
 import (
 	"io"
 	"os"
-
-	"github.com/fatih/color"
 )
 
 // WriteMarkerOption is functional option type for WriteMarker
 type WriteMarkerOption func(*WriteMarker)
 
-// MarkRule contains marking information to be applied on log stream
+// WithOverlapPolicy sets the policy applied when spans of different rules overlap
+func WithOverlapPolicy(policy OverlapPolicy) WriteMarkerOption {
+	return func(s *WriteMarker) {
+		s.overlapPolicy = policy
+	}
+}
+
+// WithRenderer sets the Renderer that the marked text is written with, ANSIRenderer by default
+func WithRenderer(renderer Renderer) WriteMarkerOption {
+	return func(s *WriteMarker) {
+		s.renderer = renderer
+	}
+}
+
+// WithColorDepth sets the color depth of the default ANSIRenderer instead of detecting it,
+// unless the color mode turns colors off
+func WithColorDepth(depth ColorDepth) WriteMarkerOption {
+	return func(s *WriteMarker) {
+		s.colorDepth = &depth
+	}
+}
+
+// WithColorMode sets whether colors are written, ColorAuto by default
+func WithColorMode(mode ColorMode) WriteMarkerOption {
+	return func(s *WriteMarker) {
+		s.colorMode = mode
+	}
+}
+
+// MarkRule contains marking information to be applied on log stream.
+// StyleFunc, when set, chooses the style of each span instead of Style.
+// Priority is only used by OverlapPriority policy, higher priority rules win.
 type MarkRule struct {
-	Matcher MatcherFunc
-	Color   *color.Color
+	Matcher   Matcher
+	Style     Style
+	StyleFunc StyleFunc
+	Priority  int
 }
 
 // WriteMarker contains specified rules for applying them on output
 type WriteMarker struct {
-	rules []MarkRule
-	out   io.Writer
+	rules         []MarkRule
+	out           io.Writer
+	overlapPolicy OverlapPolicy
+	renderer      Renderer
+	colorMode     ColorMode
+	colorDepth    *ColorDepth
 }
 
-// NewWriteMarker creates a Marker that writes out to the given io.Writer
-func NewWriteMarker(writer io.Writer) *WriteMarker {
+// NewWriteMarker creates a Marker that writes out to the given io.Writer.
+// Colors are written as the color mode decides for the writer and degraded to the color depth of the terminal.
+func NewWriteMarker(writer io.Writer, options ...WriteMarkerOption) *WriteMarker {
 	logMarker := &WriteMarker{out: writer}
+	for _, option := range options {
+		option(logMarker)
+	}
+	if logMarker.renderer == nil {
+		depth := colorDepthFor(logMarker.colorMode, writer, os.Getenv)
+		if logMarker.colorDepth != nil && depth != DepthNone {
+			depth = *logMarker.colorDepth
+		}
+		logMarker.renderer = ANSIRenderer{Depth: depth}
+	}
 	return logMarker
 }
 
 // NewStdoutMarker creates a WriteMarker with default out as os.Stdout
-func NewStdoutMarker() *WriteMarker {
-	return NewWriteMarker(os.Stdout)
+func NewStdoutMarker(options ...WriteMarkerOption) *WriteMarker {
+	return NewWriteMarker(os.Stdout, options...)
 }
 
 // AddRule appends a rule to WriteMarker and returns itself
@@ -44,11 +90,12 @@ func (s *WriteMarker) AddRules(rules []MarkRule) {
 	s.rules = append(s.rules, rules...)
 }
 
-// Write marks the text with specified rules and writes the output to specifed out
+// Write marks the text with specified rules and writes the output to specifed out.
+// It returns len(p) on success as the whole text is written, even though the marked output is longer.
 func (s WriteMarker) Write(p []byte) (n int, err error) {
-	marked := string(p)
-	for _, rule := range s.rules {
-		marked = Mark(marked, rule.Matcher, rule.Color)
+	marked := s.renderer.Render(markSegmentsOf(string(p), s.rules, s.overlapPolicy))
+	if _, err := io.WriteString(s.out, marked); err != nil {
+		return 0, err
 	}
-	return s.out.Write([]byte(marked))
+	return len(p), nil
 }
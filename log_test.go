@@ -1,9 +1,14 @@
 package marker
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
@@ -37,9 +42,9 @@ func Test_Write(t *testing.T) {
 	blue := blueFg.SprintFunc()
 
 	mockOut := &MockLogOut{}
-	writeMarker := NewWriteMarker(mockOut)
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
 
-	writeMarker.AddRule(MarkRule{MatchAll("skydome"), redFg}).AddRule(MarkRule{MatchAll("data"), redFg})
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("skydome"), Style: FromColor(redFg)}).AddRule(MarkRule{Matcher: MatchAll("data"), Style: FromColor(redFg)})
 
 	logger := log.New(writeMarker, "", 0)
 	logger.Print("best data company is skydome")
@@ -49,13 +54,172 @@ func Test_Write(t *testing.T) {
 
 	// Testing the mark order here since we cannot assert function equality (https://golang.org/ref/spec#Comparison_operators)
 	newRules := []MarkRule{
-		{MatchAll("skydome"), blueFg}, // blue should override red because of order
-		{MatchAll("company"), redFg},
+		{Matcher: MatchAll("skydome"), Style: FromColor(blueFg)}, // blue should override red because of order
+		{Matcher: MatchAll("company"), Style: FromColor(redFg)},
 	}
 	writeMarker.AddRules(newRules)
 
-	expectedLog = fmt.Sprintf("best %s %s is %s\n", red("data"), red("company"), red(blue("skydome")))
+	expectedLog = fmt.Sprintf("best %s %s is \x1b[31m%s\n", red("data"), red("company"), blue("skydome"))
 	logger.Print("best data company is skydome")
 	assert.Equal(t, expectedLog, mockOut.actualLog)
 
 }
+
+func Test_WriteCount(t *testing.T) {
+	var out bytes.Buffer
+	writeMarker := NewWriteMarker(&out, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+
+	n, err := writeMarker.Write([]byte("an ERROR line\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, len("an ERROR line\n"), n)
+
+	out.Reset()
+	copied, err := io.Copy(writeMarker, strings.NewReader("an ERROR line\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len("an ERROR line\n")), copied)
+	assert.Equal(t, "an \x1b[31mERROR\x1b[0m line\n", out.String())
+}
+
+func Test_WriteEscaped(t *testing.T) {
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
+
+	r, _ := regexp.Compile(`\d+`)
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: FromColor(redFg)}).AddRule(MarkRule{Matcher: MatchRegexp(r), Style: FromColor(blueFg)})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("ERROR 3 of 4 shards failed")
+
+	expectedLog := fmt.Sprintf("%s %s of %s shards failed\n", red("ERROR"), blue("3"), blue("4"))
+	assert.Equal(t, expectedLog, mockOut.actualLog)
+
+	logger.Print(red("upstream") + " 500")
+
+	expectedLog = fmt.Sprintf("%s %s\n", red("upstream"), blue("500"))
+	assert.Equal(t, expectedLog, mockOut.actualLog)
+}
+
+func Test_WriteOverlapPolicy(t *testing.T) {
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithOverlapPolicy(OverlapFirstRule), WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
+	writeMarker.AddRules([]MarkRule{
+		{Matcher: MatchEmail(), Style: FromColor(blueFg)},
+		{Matcher: MatchAll("example"), Style: FromColor(redFg)},
+	})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("mail john@example.com for an example")
+
+	expectedLog := fmt.Sprintf("mail %s for an %s\n", blue("john@example.com"), red("example"))
+	assert.Equal(t, expectedLog, mockOut.actualLog)
+}
+
+func Test_WriteNested(t *testing.T) {
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
+	writeMarker.AddRules([]MarkRule{
+		{Matcher: MatchBracketSurrounded(), Style: FromColor(blueFg)},
+		{Matcher: MatchAll("ERROR"), Style: FromColor(redFg)},
+	})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("[ERROR from db] retrying")
+
+	expectedLog := fmt.Sprintf("\x1b[34m[%s\x1b[34m from db]\x1b[0m retrying\n", red("ERROR"))
+	assert.Equal(t, expectedLog, mockOut.actualLog)
+}
+
+func Test_WriteStyleFunc(t *testing.T) {
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+	yellowFg := color.New(color.FgYellow)
+	yellowFg.EnableColor()
+	yellow := yellowFg.SprintFunc()
+
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor))
+	writeMarker.AddRule(MarkRule{
+		Matcher:   MatchMultiple([]string{"WARN", "ERROR"}),
+		StyleFunc: StyleByText(map[string]Style{"WARN": FromColor(yellowFg), "ERROR": FromColor(redFg)}),
+	})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("WARN disk is full, ERROR write failed")
+
+	expectedLog := fmt.Sprintf("%s disk is full, %s write failed\n", yellow("WARN"), red("ERROR"))
+	assert.Equal(t, expectedLog, mockOut.actualLog)
+}
+
+func Test_WriteRenderer(t *testing.T) {
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithRenderer(HTMLRenderer{}))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("ERROR <nil>")
+
+	assert.Equal(t, "<span class=\"marker-fg-red\">ERROR</span> &lt;nil&gt;\n", mockOut.actualLog)
+}
+
+func Test_WriteColorDepth(t *testing.T) {
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(Depth16))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(RGB(250, 10, 10))})
+
+	logger := log.New(writeMarker, "", 0)
+	logger.Print("ERROR <nil>")
+	assert.Equal(t, "\x1b[91mERROR\x1b[0m <nil>\n", mockOut.actualLog)
+
+	file, err := ioutil.TempFile("", "marker")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	defer unsetColorEnv()()
+	writeMarker = NewWriteMarker(file)
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+	fmt.Fprint(writeMarker, "ERROR <nil>")
+	file.Close()
+
+	written, err := ioutil.ReadFile(file.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, "ERROR <nil>", string(written))
+}
+
+func Test_WriteColorMode(t *testing.T) {
+	mockOut := &MockLogOut{}
+	writeMarker := NewWriteMarker(mockOut, WithColorMode(ColorNever))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+	fmt.Fprint(writeMarker, "ERROR <nil>")
+	assert.Equal(t, "ERROR <nil>", mockOut.actualLog)
+
+	writeMarker = NewWriteMarker(mockOut, WithColorMode(ColorNever), WithColorDepth(Depth16))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+	fmt.Fprint(writeMarker, "ERROR <nil>")
+	assert.Equal(t, "ERROR <nil>", mockOut.actualLog)
+
+	writeMarker = NewWriteMarker(mockOut, WithColorMode(ColorAlways), WithColorDepth(Depth16))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+	fmt.Fprint(writeMarker, "ERROR <nil>")
+	assert.Equal(t, "\x1b[31mERROR\x1b[0m <nil>", mockOut.actualLog)
+}
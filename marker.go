@@ -1,38 +1,176 @@
 package marker
 
 import (
-	"fmt"
-
-	"github.com/fatih/color"
+	"strings"
 )
 
-// Mark marks the patterns that returned from MatcherFunc with colors in given string
-func Mark(str string, matcherFunc MatcherFunc, c *color.Color) string {
-	match := matcherFunc(str)
-	patterns := match.Patterns
-	colorizeStrings(patterns, c)
-	args := convertToInterfaceSlice(patterns)
-	return fmt.Sprintf(match.Template, args...)
+const resetSequence = "\x1b[0m"
+
+// Mark marks the spans that returned from Matcher with style in given string.
+// Escape sequences already in the string are skipped while matching and kept in the output.
+// Colors are written in truecolor whatever the output is, see ANSIRenderer and WriteMarker for other depths.
+func Mark(str string, matcher Matcher, style Style) string {
+	return markRules(str, []MarkRule{{Matcher: matcher, Style: style}}, OverlapSplit)
+}
+
+// MarkFunc marks the spans that returned from Matcher with the styles chosen by StyleFunc in given string
+func MarkFunc(str string, matcher Matcher, styleFunc StyleFunc) string {
+	return markRules(str, []MarkRule{{Matcher: matcher, StyleFunc: styleFunc}}, OverlapSplit)
+}
+
+// MarkMany marks each set of spans returns by a variable number of Matcher with style in given string
+func MarkMany(str string, style Style, matchers ...Matcher) string {
+	rules := make([]MarkRule, len(matchers))
+	for i, matcher := range matchers {
+		rules[i] = MarkRule{Matcher: matcher, Style: style}
+	}
+	return markRules(str, rules, OverlapSplit)
+}
+
+// Render marks given string with the rules and renders it with given Renderer
+func Render(str string, renderer Renderer, rules ...MarkRule) string {
+	return renderer.Render(markSegmentsOf(str, rules, OverlapSplit))
+}
+
+// markRules marks given string with the rules and renders it for terminals
+func markRules(str string, rules []MarkRule, policy OverlapPolicy) string {
+	return ANSIRenderer{}.Render(markSegmentsOf(str, rules, policy))
+}
+
+// markSegmentsOf finds the spans of all rules in the visible text of given string at once
+// and resolves the overlapping ones with given policy
+func markSegmentsOf(str string, rules []MarkRule, policy OverlapPolicy) Marked {
+	visible, escapes := splitEscapes(str)
+
+	var spans []markedSpan
+	for i, rule := range rules {
+		for _, span := range rule.Matcher.Spans(visible) {
+			if span.Start < 0 || span.Start >= span.End || span.End > len(visible) {
+				continue
+			}
+			spans = append(spans, markedSpan{Span: span, index: i, rule: rule, style: spanStyle(rule, span)})
+		}
+	}
+
+	return newMarked(visible, escapes, policy.resolve(spans))
+}
+
+// markSegments styles the segments of visible text and puts the escapes back to their offsets
+func markSegments(visible string, escapes []escape, segments []segment) string {
+	s := &styleStack{visible: visible, escapes: escapes}
+	for i, segment := range segments {
+		s.writeUntil(segment.from)
+		s.push(segment.from, segment.layers)
+		s.writeUntil(segment.to)
+		if i+1 == len(segments) || segments[i+1].from != segment.to {
+			s.push(segment.to, nil)
+		}
+	}
+	s.writeUntil(len(visible))
+	s.writeEscapesAt(len(visible))
+	return s.String()
+}
+
+// styleStack writes the visible text and keeps track of the styles active on it.
+// When a span ends, the styles that were active before it are restored,
+// including the ones set by the escape sequences of the original text.
+type styleStack struct {
+	strings.Builder
+	visible string
+	escapes []escape
+	offset  int
+	layers  []markedSpan
+	base    string
+}
+
+// writeUntil writes the visible text and the escapes up to given offset
+func (s *styleStack) writeUntil(offset int) {
+	for len(s.escapes) > 0 && s.escapes[0].offset < offset {
+		s.WriteString(s.visible[s.offset:s.escapes[0].offset])
+		s.offset = s.escapes[0].offset
+		s.writeEscape(s.escapes[0].sequence)
+		s.escapes = s.escapes[1:]
+	}
+	s.WriteString(s.visible[s.offset:offset])
+	s.offset = offset
+}
+
+// push replaces the active layers with given layers at given offset.
+// Layers that are still active are kept, the rest is reset.
+// Escapes at the offset are written after resetting and before the new layers.
+func (s *styleStack) push(offset int, layers []markedSpan) {
+	kept := 0
+	for kept < len(s.layers) && kept < len(layers) && sameSpan(s.layers[kept], layers[kept]) {
+		kept++
+	}
+	if kept < len(s.layers) {
+		if s.styled() {
+			s.WriteString(resetSequence)
+			s.WriteString(s.base)
+		}
+		s.layers, kept = nil, 0
+	}
+
+	s.writeEscapesAt(offset)
+
+	for _, layer := range layers[kept:] {
+		s.WriteString(StyleSequence(layer.style))
+	}
+	s.layers = layers
 }
 
-// MarkMany marks each set of patterns returns by a variable number of MatcherFunc with color in given string
-func MarkMany(str string, c *color.Color, matcherFuncs ...MatcherFunc) string {
-	for _, matcherFunc := range matcherFuncs {
-		str = Mark(str, matcherFunc, c)
+func (s *styleStack) writeEscapesAt(offset int) {
+	for len(s.escapes) > 0 && s.escapes[0].offset == offset {
+		s.writeEscape(s.escapes[0].sequence)
+		s.escapes = s.escapes[1:]
+	}
+}
+
+// writeEscape writes an escape of the original text and updates the base style.
+// Active layers are applied again if the escape resets them.
+func (s *styleStack) writeEscape(sequence string) {
+	s.WriteString(sequence)
+	parameters, ok := sgrParameters(sequence)
+	if !ok {
+		return
+	}
+	if !resetsGraphics(parameters) {
+		s.base += sequence
+		return
+	}
+	s.base = sequence
+	if strings.Trim(sequence, "\x1b[0;m") == "" {
+		s.base = ""
+	}
+	for _, layer := range s.layers {
+		s.WriteString(StyleSequence(layer.style))
 	}
-	return str
 }
 
-func colorizeStrings(strs []string, c *color.Color) {
-	for i := range strs {
-		strs[i] = c.Sprintf("%s", strs[i])
+func (s *styleStack) styled() bool {
+	for _, layer := range s.layers {
+		if StyleSequence(layer.style) != "" {
+			return true
+		}
 	}
+	return false
 }
 
-func convertToInterfaceSlice(patterns []string) []interface{} {
-	args := make([]interface{}, len(patterns))
-	for i := range patterns {
-		args[i] = patterns[i]
+func sameSpan(a, b markedSpan) bool {
+	return a.index == b.index && a.Start == b.Start && a.End == b.End
+}
+
+// spanStyle returns the style chosen for the span by StyleFunc of the rule, by its matcher or the style of the rule
+func spanStyle(rule MarkRule, span Span) Style {
+	if rule.StyleFunc != nil {
+		if style := rule.StyleFunc(span); style != nil {
+			return style
+		}
+	}
+	if styleMatcher, ok := rule.Matcher.(StyleMatcher); ok {
+		if style := styleMatcher.SpanStyle(span); style != nil {
+			return style
+		}
 	}
-	return args
+	return rule.Style
 }
@@ -3,6 +3,7 @@ package marker
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
@@ -20,30 +21,42 @@ func Test_Mark(t *testing.T) {
 
 	tests := []struct {
 		text     string
-		matcher  MatcherFunc
+		matcher  Matcher
 		expected string
 		color    *color.Color
 	}{
 		{
 			text:  "Skydome is a data company.",
 			color: blueFg,
-			matcher: func(str string) Match {
+			matcher: MatcherFunc(func(str string) Match {
 				return Match{Template: "%s is a data company.", Patterns: []string{"Skydome"}}
-			},
+			}),
 			expected: fmt.Sprintf("%s is a data company.", blue("Skydome")),
 		},
 		{
 			text:  "Skydome is Skydome. Give yourself freedom.",
 			color: redFg,
-			matcher: func(str string) Match {
+			matcher: MatcherFunc(func(str string) Match {
 				return Match{Template: "%s is %s. Give yourself freedom.", Patterns: []string{"Skydome", "Skydome"}}
-			},
+			}),
 			expected: fmt.Sprintf("%s is %s. Give yourself freedom.", red("Skydome"), red("Skydome")),
 		},
+		{
+			text:     "cpu 95% on Skydome",
+			color:    blueFg,
+			matcher:  MatchAll("Skydome"),
+			expected: fmt.Sprintf("cpu 95%% on %s", blue("Skydome")),
+		},
+		{
+			text:     "GET /search?q=100%25 took 12ms",
+			color:    redFg,
+			matcher:  MatchAll("100%25"),
+			expected: fmt.Sprintf("GET /search?q=%s took 12ms", red("100%25")),
+		},
 	}
 
 	for _, testCase := range tests {
-		actual := Mark(testCase.text, testCase.matcher, testCase.color)
+		actual := Mark(testCase.text, testCase.matcher, FromColor(testCase.color))
 		assert.Equal(t, testCase.expected, actual)
 	}
 }
@@ -60,29 +73,133 @@ func Test_MarkMany(t *testing.T) {
 	r, _ := regexp.Compile("i")
 	tests := []struct {
 		text     string
-		matchers []MatcherFunc
+		matchers []Matcher
 		expected string
 		color    *color.Color
 	}{
 		{
 			text:     "Skydome is a data company.",
 			color:    blueFg,
-			matchers: []MatcherFunc{MatchN("Skydome", 1), MatchRegexp(r)},
+			matchers: []Matcher{MatchN("Skydome", 1), MatchRegexp(r)},
 			expected: fmt.Sprintf("%s %ss a data company.", blue("Skydome"), blue("i")),
 		},
 		{
 			text:     "Skydome is Skydome. Give yourself freedom.",
 			color:    redFg,
-			matchers: []MatcherFunc{MatchAll("Skydome"), MatchAll("yourself")},
+			matchers: []Matcher{MatchAll("Skydome"), MatchAll("yourself")},
 			expected: fmt.Sprintf("%s is %s. Give %s freedom.", red("Skydome"), red("Skydome"), red("yourself")),
 		},
 	}
 
 	for _, testCase := range tests {
-		actual := MarkMany(testCase.text, testCase.color, testCase.matchers...)
+		actual := MarkMany(testCase.text, FromColor(testCase.color), testCase.matchers...)
+		assert.Equal(t, testCase.expected, actual)
+	}
+}
+func Test_MarkEscaped(t *testing.T) {
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+
+	r, _ := regexp.Compile(`\d+`)
+	tests := []struct {
+		text     string
+		matcher  Matcher
+		expected string
+	}{
+		{
+			text:     red("3 errors") + " in 13 files",
+			matcher:  MatchAll("3"),
+			expected: fmt.Sprintf("\x1b[31m%s\x1b[31m errors\x1b[0m in 1%s files", blue("3"), blue("3")),
+		},
+		{
+			text:     red("3 errors") + " in 13 files",
+			matcher:  MatchRegexp(r),
+			expected: fmt.Sprintf("\x1b[31m%s\x1b[31m errors\x1b[0m in %s files", blue("3"), blue("13")),
+		},
+		{
+			text:     "Sky\x1b[1mdome\x1b[22m is a data company.",
+			matcher:  MatchAll("Skydome"),
+			expected: fmt.Sprintf("%s\x1b[1m\x1b[22m is a data company.", blue("Sky\x1b[1mdome")),
+		},
+	}
+
+	for _, testCase := range tests {
+		actual := Mark(testCase.text, testCase.matcher, FromColor(blueFg))
 		assert.Equal(t, testCase.expected, actual)
 	}
+
+	marked := MarkMany("cpu 31% on node 3", FromColor(blueFg), MatchAll("cpu"), MatchAll("3"))
+	expected := fmt.Sprintf("%s %s1%% on node %s", blue("cpu"), blue("3"), blue("3"))
+	assert.Equal(t, expected, marked)
 }
+
+func Test_MarkNested(t *testing.T) {
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+
+	boldFg := color.New(color.Bold)
+	boldFg.EnableColor()
+
+	whiteBg := color.New(color.BgWhite)
+	whiteBg.EnableColor()
+
+	str := "[request ERROR in ERROR handler] done"
+
+	marked := Mark(Mark(str, MatchAll("ERROR"), FromColor(redFg)), MatchBracketSurrounded(), FromColor(blueFg))
+	expected := fmt.Sprintf("\x1b[34m[request %s\x1b[34m in %s\x1b[34m handler]\x1b[0m done", red("ERROR"), red("ERROR"))
+	assert.Equal(t, expected, marked)
+
+	marked = Mark(Mark(str, MatchBracketSurrounded(), FromColor(blueFg)), MatchAll("ERROR"), FromColor(redFg))
+	expected = fmt.Sprintf("\x1b[34m[request %s\x1b[34m in %s\x1b[34m handler]\x1b[0m done", red("ERROR"), red("ERROR"))
+	assert.Equal(t, expected, marked)
+
+	b := &MarkBuilder{}
+	marked = b.SetString(str).
+		Mark(MatchBracketSurrounded(), FromColor(whiteBg)).
+		Mark(MatchAll("ERROR"), FromColor(boldFg)).
+		Build()
+	expected = "\x1b[47m[request \x1b[1mERROR\x1b[0m\x1b[47m in \x1b[1mERROR\x1b[0m\x1b[47m handler]\x1b[0m done"
+	assert.Equal(t, expected, marked)
+
+	marked = MarkMany("overlapping", FromColor(blueFg), MatchAll("overlap"), MatchAll("lapping"))
+	expected = "\x1b[34mover\x1b[34mlap\x1b[0m\x1b[34mping\x1b[0m"
+	assert.Equal(t, expected, marked)
+}
+
+func Test_MarkRegexpGroups(t *testing.T) {
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+
+	whiteFg := color.New(color.FgWhite)
+	whiteFg.EnableColor()
+	white := whiteFg.SprintFunc()
+
+	r := regexp.MustCompile(`(?P<key>\w+)=(?P<value>\w+)`)
+	matcher := MatchRegexpGroups(r, map[string]Style{"key": FromColor(blueFg), "value": FromColor(redFg)})
+
+	marked := Mark("level=warn cpu=95%", matcher, nil)
+	expected := fmt.Sprintf("%s=%s %s=%s%%", blue("level"), red("warn"), blue("cpu"), red("95"))
+	assert.Equal(t, expected, marked)
+
+	marked = Mark("level=warn", matcher, FromColor(whiteFg))
+	expected = fmt.Sprintf("%s%s%s", blue("level"), white("="), red("warn"))
+	assert.Equal(t, expected, marked)
+}
+
 func Benchmark_Mark(b *testing.B) {
 	blueFg := color.New(color.FgBlue)
 	blueFg.EnableColor()
@@ -102,7 +219,7 @@ func Benchmark_Mark(b *testing.B) {
 	}
 
 	for i := 0; i < b.N; i++ {
-		Mark(data.text, data.matcher, data.color)
+		Mark(data.text, data.matcher, FromColor(data.color))
 	}
 }
 func Benchmark_MarkMany(b *testing.B) {
@@ -112,16 +229,26 @@ func Benchmark_MarkMany(b *testing.B) {
 
 	data := struct {
 		text     string
-		matchers []MatcherFunc
+		matchers []Matcher
 		expected []string
 		color    *color.Color
 	}{
 		text:     "Skydome is a data company.",
 		color:    blueFg,
-		matchers: []MatcherFunc{MatchAll("Skydome"), MatchAll("yourself")},
+		matchers: []Matcher{MatchAll("Skydome"), MatchAll("yourself")},
+	}
+
+	for i := 0; i < b.N; i++ {
+		MarkMany(data.text, FromColor(data.color), data.matchers...)
 	}
+}
+
+func Benchmark_MarkManyMatches(b *testing.B) {
+	b.ReportAllocs()
+	text := strings.Repeat("ab ", 16000)
+	matcher := MatchAll("ab")
 
 	for i := 0; i < b.N; i++ {
-		MarkMany(data.text, data.color, data.matchers...)
+		Mark(text, matcher, Fg(Blue))
 	}
 }
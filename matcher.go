@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // MatcherFunc returns a Match which contains information about found patterns
@@ -16,143 +18,312 @@ type Match struct {
 	Patterns []string
 }
 
-// MatchAll creates a MatcherFunc that matches all patterns in given string
-func MatchAll(pattern string) MatcherFunc {
-	return func(str string) Match {
-		count := strings.Count(str, pattern)
-		return Match{
-			Template: strings.ReplaceAll(str, pattern, "%s"),
-			Patterns: fillSlice(make([]string, count), pattern),
-		}
+// Spans adapts the Match returned by MatcherFunc into Spans of given string
+func (f MatcherFunc) Spans(str string) []Span {
+	return templateSpans(str, f(str))
+}
+
+// Span contains the byte offsets and the text of a found pattern in a string
+type Span struct {
+	Start int
+	End   int
+	Text  string
+	Meta  map[string]string
+}
+
+// Matcher finds the Spans of patterns to be marked in given string
+type Matcher interface {
+	Spans(str string) []Span
+}
+
+// StyleMatcher is a Matcher that also chooses the styles of the spans it finds.
+// Spans that it returns no style for are marked with the style given along with the matcher.
+type StyleMatcher interface {
+	Matcher
+	SpanStyle(span Span) Style
+}
+
+// SpanMatcherFunc returns the Spans of found patterns in given string
+type SpanMatcherFunc func(string) []Span
+
+// Spans calls the SpanMatcherFunc with given string
+func (f SpanMatcherFunc) Spans(str string) []Span {
+	return f(str)
+}
+
+// MatchAll creates a SpanMatcherFunc that matches all patterns in given string
+func MatchAll(pattern string) SpanMatcherFunc {
+	return func(str string) []Span {
+		return findAll(str, pattern, -1)
 	}
 }
 
-// MatchN creates a MatcherFunc that matches first n patterns in given string
-func MatchN(pattern string, n int) MatcherFunc {
-	return func(str string) Match {
-		count := min(n, strings.Count(str, pattern))
-		return Match{
-			Template: strings.Replace(str, pattern, "%s", n),
-			Patterns: fillSlice(make([]string, count), pattern),
-		}
+// MatchN creates a SpanMatcherFunc that matches first n patterns in given string
+func MatchN(pattern string, n int) SpanMatcherFunc {
+	return func(str string) []Span {
+		return findAll(str, pattern, n)
 	}
 }
 
-// MatchMultiple creates a MatcherFunc that matches all string patterns from given slice in given string
-func MatchMultiple(patternsToMatch []string) MatcherFunc {
-	return func(str string) Match {
+// MatchMultiple creates a SpanMatcherFunc that matches all string patterns from given slice in given string
+func MatchMultiple(patternsToMatch []string) SpanMatcherFunc {
+	return func(str string) []Span {
 		patternMatchIndexes := findPatternMatchIndexes(str, patternsToMatch)
-		patterns := getPatternsInOrder(patternMatchIndexes)
-		return Match{
-			Template: replaceMultiple(str, patternsToMatch, "%s"),
-			Patterns: patterns,
+		matchIndexes := getKeys(patternMatchIndexes)
+		sort.Ints(matchIndexes)
+		spans := make([]Span, 0, len(matchIndexes))
+		for _, index := range matchIndexes {
+			pattern := patternMatchIndexes[index]
+			spans = append(spans, Span{Start: index, End: index + len(pattern), Text: pattern})
 		}
+		return spans
 	}
 }
 
-// MatchRegexp creates a MatcherFunc that matches given regexp in given string
-func MatchRegexp(r *regexp.Regexp) MatcherFunc {
-	return func(str string) Match {
-		return Match{
-			Template: r.ReplaceAllString(str, "%s"),
-			Patterns: r.FindAllString(str, -1),
+// MatchRegexp creates a SpanMatcherFunc that matches given regexp in given string
+func MatchRegexp(r *regexp.Regexp) SpanMatcherFunc {
+	return func(str string) []Span {
+		return spansFromIndexes(str, r.FindAllStringIndex(str, -1))
+	}
+}
+
+// RegexpGroupMatcher is a StyleMatcher that styles the capture groups of a regexp separately
+type RegexpGroupMatcher struct {
+	regexp *regexp.Regexp
+	styles map[string]Style
+}
+
+// MatchRegexpGroups creates a RegexpGroupMatcher that matches given regexp and styles each capture group
+// with the style keyed by its name, or by its number for unnamed groups. Text of the match outside
+// of the styled groups is marked with the style given along with the matcher.
+func MatchRegexpGroups(r *regexp.Regexp, styles map[string]Style) *RegexpGroupMatcher {
+	return &RegexpGroupMatcher{regexp: r, styles: styles}
+}
+
+// Spans returns the styled groups and the rest of each match as separate spans.
+// The spans of groups have the key of their style as "group" in Meta.
+func (m *RegexpGroupMatcher) Spans(str string) []Span {
+	names := m.regexp.SubexpNames()
+	var spans []Span
+	for _, index := range m.regexp.FindAllStringSubmatchIndex(str, -1) {
+		// later groups are nested in the earlier ones, so the innermost styled group owns each byte
+		owners := make([]string, index[1]-index[0])
+		for group := 1; group < len(names); group++ {
+			start, end := index[2*group], index[2*group+1]
+			if start < 0 {
+				continue
+			}
+			key := m.groupKey(names, group)
+			if key == "" {
+				continue
+			}
+			for i := start; i < end; i++ {
+				owners[i-index[0]] = key
+			}
 		}
+
+		spans = append(spans, ownerSpans(str, index[0], owners)...)
 	}
+	return spans
 }
 
-// MatchTimestamp creates a MatcherFunc that matches given time layout pattern in given string
-func MatchTimestamp(layout string) MatcherFunc {
-	return func(str string) Match {
-		r := timestampLayoutRegexps[layout]
-		return MatchRegexp(r)(str)
+// ownerSpans splits the text of given string that starts at the offset into a span for each run of bytes
+// with the same owner, which is the key of the style of the run, and stores the owner as "group" in Meta
+func ownerSpans(str string, offset int, owners []string) []Span {
+	var spans []Span
+	runStart := 0
+	for i := 1; i <= len(owners); i++ {
+		if i < len(owners) && owners[i] == owners[runStart] {
+			continue
+		}
+		span := Span{Start: offset + runStart, End: offset + i}
+		span.Text = str[span.Start:span.End]
+		if owners[runStart] != "" {
+			span.Meta = map[string]string{"group": owners[runStart]}
+		}
+		spans = append(spans, span)
+		runStart = i
 	}
+	return spans
+}
+
+// SpanStyle returns the style of the group of given span
+func (m *RegexpGroupMatcher) SpanStyle(span Span) Style {
+	return m.styles[span.Meta["group"]]
 }
 
-// MatchSurrounded creates a MatcherFunc that matches the patterns surrounded by given opening and closure strings
-func MatchSurrounded(opening string, closure string) MatcherFunc {
-	return func(str string) Match {
-		metaEscapedOpening := regexp.QuoteMeta(opening)
-		metaEscapedClosure := regexp.QuoteMeta(closure)
-		matchPattern := fmt.Sprintf("%s[^%s]*%s", metaEscapedOpening, metaEscapedOpening, metaEscapedClosure)
-		r, _ := regexp.Compile(matchPattern)
+func (m *RegexpGroupMatcher) groupKey(names []string, group int) string {
+	if _, ok := m.styles[names[group]]; ok && names[group] != "" {
+		return names[group]
+	}
+	if _, ok := m.styles[strconv.Itoa(group)]; ok {
+		return strconv.Itoa(group)
+	}
+	return ""
+}
+
+// MatchTimestamp creates a SpanMatcherFunc that matches given time layout pattern in given string
+func MatchTimestamp(layout string) SpanMatcherFunc {
+	return func(str string) []Span {
+		r, ok := timestampLayoutRegexps[layout]
+		if !ok {
+			return nil
+		}
 		return MatchRegexp(r)(str)
 	}
 }
 
+// MatchSurrounded creates a SpanMatcherFunc that matches the patterns surrounded by given opening and closure strings.
+// It matches nothing when the opening or closure string is empty.
+func MatchSurrounded(opening string, closure string) SpanMatcherFunc {
+	metaEscapedOpening := regexp.QuoteMeta(opening)
+	metaEscapedClosure := regexp.QuoteMeta(closure)
+	matchPattern := fmt.Sprintf("%s[^%s]*%s", metaEscapedOpening, metaEscapedOpening, metaEscapedClosure)
+	r, err := regexp.Compile(matchPattern)
+	if opening == "" || closure == "" || err != nil {
+		return func(string) []Span { return nil }
+	}
+	return MatchRegexp(r)
+}
+
 // MatchBracketSurrounded is a helper utility for easy matching of bracket surrounded text
-func MatchBracketSurrounded() MatcherFunc {
+func MatchBracketSurrounded() SpanMatcherFunc {
 	return MatchSurrounded("[", "]")
 }
 
 // MatchParensSurrounded is a helper utility for easy matching text surrounded in parentheses
-func MatchParensSurrounded() MatcherFunc {
+func MatchParensSurrounded() SpanMatcherFunc {
 	return MatchSurrounded("(", ")")
 }
 
-// MatchEmail creates a MatcherFunc that matches emails which meets the conditions of RFC5322 standard
-func MatchEmail() MatcherFunc {
-	return func(str string) Match {
-		return MatchRegexp(EmailRegexp)(str)
-	}
+// MatchEmail creates a SpanMatcherFunc that matches emails which meets the conditions of RFC5322 standard
+func MatchEmail() SpanMatcherFunc {
+	return MatchRegexp(EmailRegexp)
 }
 
 var daysOfWeek = [14]string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
 	"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
 
-// MatchDaysOfWeek creates a MatcherFunc that matches days of the week in given string
-func MatchDaysOfWeek() MatcherFunc {
-	return func(str string) Match {
-		return MatchMultiple(daysOfWeek[:])(str)
+// MatchDaysOfWeek creates a SpanMatcherFunc that matches days of the week in given string
+func MatchDaysOfWeek() SpanMatcherFunc {
+	return MatchMultiple(daysOfWeek[:])
+}
+
+func findAll(str string, pattern string, n int) []Span {
+	if pattern == "" {
+		return nil
 	}
+	var spans []Span
+	offset := 0
+	for n < 0 || len(spans) < n {
+		index := strings.Index(str[offset:], pattern)
+		if index < 0 {
+			break
+		}
+		start := offset + index
+		offset = start + len(pattern)
+		spans = append(spans, Span{Start: start, End: offset, Text: pattern})
+	}
+	return spans
 }
 
-func findPatternMatchIndexes(str string, patternsToMatch []string) map[int]string {
-	patternMatchIndexes := make(map[int]string)
-	pattern := strings.Join(patternsToMatch , "|")
-	patternRegex := regexp.MustCompile(pattern)
-	indices := patternRegex.FindAllStringIndex(str, -1)
-	for _, v := range(indices) {
-		start, end := v[0], v[1]
-		patternMatchIndexes[start] = str[start:end]
+func spansFromIndexes(str string, indexes [][]int) []Span {
+	var spans []Span
+	for _, index := range indexes {
+		start, end := index[0], index[1]
+		spans = append(spans, Span{Start: start, End: end, Text: str[start:end]})
 	}
-	return patternMatchIndexes
+	return spans
 }
 
-func getPatternsInOrder(patternMatchIndexes map[int]string) []string {
-	matchIndexes := getKeys(patternMatchIndexes)
-	sort.Ints(matchIndexes)
-	patterns := make([]string, 0, len(patternMatchIndexes))
-	for _, index := range matchIndexes {
-		patterns = append(patterns, patternMatchIndexes[index])
+// matchTokens creates a SpanMatcherFunc that matches the candidates of given regexp which are whole tokens.
+// A candidate is skipped when a word character or a character in notBefore precedes it, or when a word character
+// follows it or a character in notAfter that is followed by a word character. validate returns the part of
+// a candidate to match, or an empty string to skip it.
+func matchTokens(r *regexp.Regexp, notBefore, notAfter string, validate func(token string) string) SpanMatcherFunc {
+	return func(str string) []Span {
+		var spans []Span
+		for _, index := range r.FindAllStringIndex(str, -1) {
+			start := index[0]
+			if start > 0 && (isWordByte(str[start-1]) || strings.IndexByte(notBefore, str[start-1]) >= 0) {
+				continue
+			}
+			token := validate(str[start:index[1]])
+			if token == "" {
+				continue
+			}
+			end := start + len(token)
+			if end < len(str) && (isWordByte(str[end]) ||
+				strings.IndexByte(notAfter, str[end]) >= 0 && end+1 < len(str) && isWordByte(str[end+1])) {
+				continue
+			}
+			spans = append(spans, Span{Start: start, End: end, Text: token})
+		}
+		return spans
 	}
-	return patterns
 }
 
-func getKeys(m map[int]string) []int {
-	keys := make([]int, 0, len(m))
-	for key := range m {
-		keys = append(keys, key)
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b >= utf8.RuneSelf
+}
+
+// mergeSpans returns the spans of first along with the spans of second that overlap none of them, sorted by offset
+func mergeSpans(first, second []Span) []Span {
+	spans := append([]Span(nil), first...)
+	for _, span := range second {
+		overlaps := false
+		for _, other := range first {
+			overlaps = overlaps || span.Start < other.End && other.Start < span.End
+		}
+		if !overlaps {
+			spans = append(spans, span)
+		}
 	}
-	return keys
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+	return spans
 }
 
-func replaceMultiple(str string, patterns []string, with string) string {
-	for _, patternToMatch := range patterns {
-		str = strings.ReplaceAll(str, patternToMatch, with)
+// templateSpans walks the template of a Match along with the string it was created from
+// and locates each pattern at the position of its %s placeholder
+func templateSpans(str string, match Match) []Span {
+	var spans []Span
+	template, patterns := match.Template, match.Patterns
+	i, j := 0, 0
+	for i < len(template) {
+		if len(spans) < len(patterns) && strings.HasPrefix(template[i:], "%s") {
+			pattern := patterns[len(spans)]
+			if strings.HasPrefix(str[j:], pattern) {
+				spans = append(spans, Span{Start: j, End: j + len(pattern), Text: pattern})
+				i, j = i+2, j+len(pattern)
+				continue
+			}
+		}
+		if j >= len(str) || template[i] != str[j] {
+			break
+		}
+		i, j = i+1, j+1
 	}
-	return str
+	return spans
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+func findPatternMatchIndexes(str string, patternsToMatch []string) map[int]string {
+	patternMatchIndexes := make(map[int]string)
+	pattern := strings.Join(patternsToMatch, "|")
+	patternRegex := regexp.MustCompile(pattern)
+	indices := patternRegex.FindAllStringIndex(str, -1)
+	for _, v := range indices {
+		start, end := v[0], v[1]
+		patternMatchIndexes[start] = str[start:end]
 	}
-	return b
+	return patternMatchIndexes
 }
 
-func fillSlice(s []string, v string) []string {
-	for i := range s {
-		s[i] = v
+func getKeys(m map[int]string) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	return s
+	return keys
 }
@@ -10,34 +10,80 @@ import (
 
 func Test_MatchAll(t *testing.T) {
 	str := "Skydome is Skydome"
-	actualMatch := MatchAll("Skydome")(str)
-	expectedMatch := Match{Template: "%s is %s", Patterns: []string{"Skydome", "Skydome"}}
+	actualSpans := MatchAll("Skydome")(str)
+	expectedSpans := []Span{
+		{Start: 0, End: 7, Text: "Skydome"},
+		{Start: 11, End: 18, Text: "Skydome"},
+	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchN(t *testing.T) {
 	str := "Skydome is Skydome"
-	actualMatch := MatchN("Skydome", 1)(str)
-	expectedMatch := Match{Template: "%s is Skydome", Patterns: []string{"Skydome"}}
-	assert.Equal(t, expectedMatch, actualMatch)
-
-	actualMatch = MatchN("Skydome", 2)(str)
-	expectedMatch = Match{Template: "%s is %s", Patterns: []string{"Skydome", "Skydome"}}
-	assert.Equal(t, expectedMatch, actualMatch)
+	actualSpans := MatchN("Skydome", 1)(str)
+	expectedSpans := []Span{{Start: 0, End: 7, Text: "Skydome"}}
+	assert.Equal(t, expectedSpans, actualSpans)
+
+	actualSpans = MatchN("Skydome", 2)(str)
+	expectedSpans = []Span{
+		{Start: 0, End: 7, Text: "Skydome"},
+		{Start: 11, End: 18, Text: "Skydome"},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
 
-	actualMatch = MatchN("Skydome", 3)(str)
-	assert.Equal(t, expectedMatch, actualMatch)
+	actualSpans = MatchN("Skydome", 3)(str)
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchRegexp(t *testing.T) {
 	str := "I scream, you all scream, we all scream for ice cream."
 
 	r, _ := regexp.Compile("([a-z]?cream)")
-	actualMatch := MatchRegexp(r)(str)
-	expectedMatch := Match{Template: "I %s, you all %s, we all %s for ice %s.", Patterns: []string{"scream", "scream", "scream", "cream"}}
+	actualSpans := MatchRegexp(r)(str)
+	expectedSpans := []Span{
+		{Start: 2, End: 8, Text: "scream"},
+		{Start: 18, End: 24, Text: "scream"},
+		{Start: 33, End: 39, Text: "scream"},
+		{Start: 48, End: 53, Text: "cream"},
+	}
+
+	assert.Equal(t, expectedSpans, actualSpans)
+}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+func Test_MatchRegexpGroups(t *testing.T) {
+	cyanFg := Fg(Cyan)
+	yellowFg := Fg(Yellow)
+
+	str := "level=info took=12ms"
+	r := regexp.MustCompile(`(?P<key>\w+)=(?P<value>\w+)`)
+	matcher := MatchRegexpGroups(r, map[string]Style{"key": cyanFg, "value": yellowFg})
+
+	actualSpans := matcher.Spans(str)
+	expectedSpans := []Span{
+		{Start: 0, End: 5, Text: "level", Meta: map[string]string{"group": "key"}},
+		{Start: 5, End: 6, Text: "="},
+		{Start: 6, End: 10, Text: "info", Meta: map[string]string{"group": "value"}},
+		{Start: 11, End: 15, Text: "took", Meta: map[string]string{"group": "key"}},
+		{Start: 15, End: 16, Text: "="},
+		{Start: 16, End: 20, Text: "12ms", Meta: map[string]string{"group": "value"}},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
+	assert.Equal(t, cyanFg, matcher.SpanStyle(actualSpans[0]))
+	assert.Nil(t, matcher.SpanStyle(actualSpans[1]))
+	assert.Equal(t, yellowFg, matcher.SpanStyle(actualSpans[2]))
+
+	str = "panic at main.go:123"
+	r = regexp.MustCompile(`(([\w/]+)\.go):(\d+)`)
+	matcher = MatchRegexpGroups(r, map[string]Style{"1": cyanFg, "3": yellowFg})
+
+	actualSpans = matcher.Spans(str)
+	expectedSpans = []Span{
+		{Start: 9, End: 16, Text: "main.go", Meta: map[string]string{"group": "1"}},
+		{Start: 16, End: 17, Text: ":"},
+		{Start: 17, End: 20, Text: "123", Meta: map[string]string{"group": "3"}},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchTimestamp(t *testing.T) {
@@ -45,283 +91,301 @@ func Test_MatchTimestamp(t *testing.T) {
 
 	t.Run("ANSIC", func(t *testing.T) {
 		str := "Current timestamp is Mon Jan 31 20:59:00 2006"
-		match := MatchTimestamp(time.ANSIC)(str)
+		spans := MatchTimestamp(time.ANSIC)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Mon Jan 31 20:59:00 2006"},
+		expectedSpans := []Span{
+			{Start: 21, End: 45, Text: "Mon Jan 31 20:59:00 2006"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("UnixDate", func(t *testing.T) {
 		str := "Current timestamp is Mon Jan _2 03:04:05 MST 2006"
-		match := MatchTimestamp(time.UnixDate)(str)
+		spans := MatchTimestamp(time.UnixDate)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Mon Jan _2 03:04:05 MST 2006"},
+		expectedSpans := []Span{
+			{Start: 21, End: 49, Text: "Mon Jan _2 03:04:05 MST 2006"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RubyDate", func(t *testing.T) {
 		str := "Current timestamp is Fri Feb 04 03:04:05 -0300 2006"
-		match := MatchTimestamp(time.RubyDate)(str)
+		spans := MatchTimestamp(time.RubyDate)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Fri Feb 04 03:04:05 -0300 2006"},
+		expectedSpans := []Span{
+			{Start: 21, End: 51, Text: "Fri Feb 04 03:04:05 -0300 2006"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC822", func(t *testing.T) {
 		str := "Current timestamp is 02 Jan 06 05:04 MST"
-		match := MatchTimestamp(time.RFC822)(str)
+		spans := MatchTimestamp(time.RFC822)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"02 Jan 06 05:04 MST"},
+		expectedSpans := []Span{
+			{Start: 21, End: 40, Text: "02 Jan 06 05:04 MST"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC822Z", func(t *testing.T) {
 		str := "Current timestamp is 02 Jan 06 15:04 -0300"
-		match := MatchTimestamp(time.RFC822Z)(str)
+		spans := MatchTimestamp(time.RFC822Z)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"02 Jan 06 15:04 -0300"},
+		expectedSpans := []Span{
+			{Start: 21, End: 42, Text: "02 Jan 06 15:04 -0300"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC850", func(t *testing.T) {
 		str := "Current timestamp is Saturday, 07-Aug-06 22:04:59 MST"
-		match := MatchTimestamp(time.RFC850)(str)
+		spans := MatchTimestamp(time.RFC850)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Saturday, 07-Aug-06 22:04:59 MST"},
+		expectedSpans := []Span{
+			{Start: 21, End: 53, Text: "Saturday, 07-Aug-06 22:04:59 MST"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC1123", func(t *testing.T) {
 		str := "Current timestamp is Mon, 02 Jan 2006 15:04:05 MST"
-		match := MatchTimestamp(time.RFC1123)(str)
+		spans := MatchTimestamp(time.RFC1123)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Mon, 02 Jan 2006 15:04:05 MST"},
+		expectedSpans := []Span{
+			{Start: 21, End: 50, Text: "Mon, 02 Jan 2006 15:04:05 MST"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC1123Z", func(t *testing.T) {
 		str := "Current timestamp is Mon, 02 Jan 2006 15:04:05 -0300"
-		match := MatchTimestamp(time.RFC1123Z)(str)
+		spans := MatchTimestamp(time.RFC1123Z)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Mon, 02 Jan 2006 15:04:05 -0300"},
+		expectedSpans := []Span{
+			{Start: 21, End: 52, Text: "Mon, 02 Jan 2006 15:04:05 -0300"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC3339", func(t *testing.T) {
-		str := "Current timestamp is 2006-01-02T15:04:05Z07:00"
-		match := MatchTimestamp(time.RFC3339)(str)
+		str := "Current timestamp is 2006-01-02T15:04:05+07:00, was 2024-01-02T03:04:05Z"
+		spans := MatchTimestamp(time.RFC3339)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"2006-01-02T15:04:05Z07:00"},
+		expectedSpans := []Span{
+			{Start: 21, End: 46, Text: "2006-01-02T15:04:05+07:00"},
+			{Start: 52, End: 72, Text: "2024-01-02T03:04:05Z"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("RFC3339Nano", func(t *testing.T) {
-		str := "Current timestamp is 2006-01-02T15:04:05.999999999Z07:00"
-		match := MatchTimestamp(time.RFC3339Nano)(str)
+		str := "Current timestamp is 2006-01-02T15:04:05.999999999-07:00, was 2024-01-02T03:04:05.5Z"
+		spans := MatchTimestamp(time.RFC3339Nano)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"2006-01-02T15:04:05.999999999Z07:00"},
+		expectedSpans := []Span{
+			{Start: 21, End: 56, Text: "2006-01-02T15:04:05.999999999-07:00"},
+			{Start: 62, End: 84, Text: "2024-01-02T03:04:05.5Z"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("Kitchen", func(t *testing.T) {
 		str := "Current timestamp is 2:15PM"
-		match := MatchTimestamp(time.Kitchen)(str)
+		spans := MatchTimestamp(time.Kitchen)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"2:15PM"},
+		expectedSpans := []Span{
+			{Start: 21, End: 27, Text: "2:15PM"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("Stamp", func(t *testing.T) {
 		str := "Current timestamp is Jan _2 15:04:05 and Jan _2 15:04:10"
-		match := MatchTimestamp(time.Stamp)(str)
+		spans := MatchTimestamp(time.Stamp)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %sand %s",
-			Patterns: []string{"Jan _2 15:04:05 ", "Jan _2 15:04:10"},
+		expectedSpans := []Span{
+			{Start: 21, End: 37, Text: "Jan _2 15:04:05 "},
+			{Start: 41, End: 56, Text: "Jan _2 15:04:10"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 
 		str = "Stamps Jan _2 15:04:05.999 and Jan _2 15:04:05.999999 and Jan _2 15:04:05.999999999"
-		match = MatchTimestamp(time.Stamp)(str)
-
-		expectedMatch = Match{
-			Template: "Stamps Jan _2 15:04:05.999 and Jan _2 15:04:05.999999 and Jan _2 15:04:05.999999999",
-			Patterns: nil,
-		}
+		spans = MatchTimestamp(time.Stamp)(str)
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Empty(t, spans)
 	})
 
 	t.Run("StampMilli", func(t *testing.T) {
 		str := "Current timestamp is Jan _2 15:04:05.999"
-		match := MatchTimestamp(time.StampMilli)(str)
+		spans := MatchTimestamp(time.StampMilli)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Jan _2 15:04:05.999"},
+		expectedSpans := []Span{
+			{Start: 21, End: 40, Text: "Jan _2 15:04:05.999"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("StampMicro", func(t *testing.T) {
 		str := "Current timestamp is Jan _2 15:04:05.999999"
-		match := MatchTimestamp(time.StampMicro)(str)
+		spans := MatchTimestamp(time.StampMicro)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Jan _2 15:04:05.999999"},
+		expectedSpans := []Span{
+			{Start: 21, End: 43, Text: "Jan _2 15:04:05.999999"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
 	})
 
 	t.Run("StampNano", func(t *testing.T) {
 		str := "Current timestamp is Jan _2 15:04:05.000000000"
-		match := MatchTimestamp(time.StampNano)(str)
+		spans := MatchTimestamp(time.StampNano)(str)
 
-		expectedMatch := Match{
-			Template: "Current timestamp is %s",
-			Patterns: []string{"Jan _2 15:04:05.000000000"},
+		expectedSpans := []Span{
+			{Start: 21, End: 46, Text: "Jan _2 15:04:05.000000000"},
 		}
 
-		assert.Equal(t, expectedMatch, match)
+		assert.Equal(t, expectedSpans, spans)
+	})
+
+	t.Run("Unknown layout", func(t *testing.T) {
+		str := "Current timestamp is 2006-01-02"
+		spans := MatchTimestamp("2006-01-02")(str)
+
+		assert.Empty(t, spans)
 	})
 }
 
 func Test_MatchSurrounded(t *testing.T) {
 	str := "[ERROR] This is a -debug- message -(and it's okay)- [INFO] --test--"
 
-	actualMatch := MatchSurrounded("-", "-")(str)
+	actualSpans := MatchSurrounded("-", "-")(str)
 
-	expectedMatch := Match{
-		Template: "[ERROR] This is a %s message %s [INFO] %stest%s",
-		Patterns: []string{"-debug-", "-(and it's okay)-", "--", "--"},
+	expectedSpans := []Span{
+		{Start: 18, End: 25, Text: "-debug-"},
+		{Start: 34, End: 51, Text: "-(and it's okay)-"},
+		{Start: 59, End: 61, Text: "--"},
+		{Start: 65, End: 67, Text: "--"},
 	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 
 	str = "abcMULTIPLE CHARACTERSdef whoa"
 
-	actualMatch = MatchSurrounded("abc", "def")(str)
+	actualSpans = MatchSurrounded("abc", "def")(str)
 
-	expectedMatch = Match{
-		Template: "%s whoa",
-		Patterns: []string{"abcMULTIPLE CHARACTERSdef"},
+	expectedSpans = []Span{
+		{Start: 0, End: 25, Text: "abcMULTIPLE CHARACTERSdef"},
 	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 
 	str = "[[DOUBLE CHARACTERS]]"
 
-	actualMatch = MatchSurrounded("[[", "]]")(str)
+	actualSpans = MatchSurrounded("[[", "]]")(str)
 
-	expectedMatch = Match{
-		Template: "%s",
-		Patterns: []string{"[[DOUBLE CHARACTERS]]"},
+	expectedSpans = []Span{
+		{Start: 0, End: 21, Text: "[[DOUBLE CHARACTERS]]"},
 	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
+
+	assert.Empty(t, MatchSurrounded("", "]")("a]b"))
+	assert.Empty(t, MatchSurrounded("[", "")("[a"))
 }
 
 func Test_MatchBracketSurrounded(t *testing.T) {
 	str := "[ERROR] This is a -debug- message (and it's okay) [INFO] --test--"
 
-	actualMatch := MatchBracketSurrounded()(str)
+	actualSpans := MatchBracketSurrounded()(str)
 
-	expectedMatch := Match{
-		Template: "%s This is a -debug- message (and it's okay) %s --test--",
-		Patterns: []string{"[ERROR]", "[INFO]"},
+	expectedSpans := []Span{
+		{Start: 0, End: 7, Text: "[ERROR]"},
+		{Start: 50, End: 56, Text: "[INFO]"},
 	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchParensSurrounded(t *testing.T) {
 	str := "[ERROR] This is a -debug- message (and it's okay) [INFO] --test--"
 
-	actualMatch := MatchParensSurrounded()(str)
+	actualSpans := MatchParensSurrounded()(str)
 
-	expectedMatch := Match{
-		Template: "[ERROR] This is a -debug- message %s [INFO] --test--",
-		Patterns: []string{"(and it's okay)"},
+	expectedSpans := []Span{
+		{Start: 34, End: 49, Text: "(and it's okay)"},
 	}
 
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchDaysOfWeek(t *testing.T) {
 	str := "Today is Tuesday or tuesday not tUesday"
-	actualMatch := MatchDaysOfWeek()(str)
-	expectedMatch := Match{Template: "Today is %s or %s not tUesday", Patterns: []string{"Tuesday", "tuesday"}}
-	assert.Equal(t, actualMatch, expectedMatch)
+	actualSpans := MatchDaysOfWeek()(str)
+	expectedSpans := []Span{
+		{Start: 9, End: 16, Text: "Tuesday"},
+		{Start: 20, End: 27, Text: "tuesday"},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
 	str = "Today is Tuesday or tuesday not tUesday but Tuesday"
-	actualMatch = MatchDaysOfWeek()(str)
-	expectedMatch = Match{Template: "Today is %s or %s not tUesday but %s", Patterns: []string{"Tuesday", "tuesday", "Tuesday"}}
-	assert.Equal(t, expectedMatch, actualMatch)
+	actualSpans = MatchDaysOfWeek()(str)
+	expectedSpans = []Span{
+		{Start: 9, End: 16, Text: "Tuesday"},
+		{Start: 20, End: 27, Text: "tuesday"},
+		{Start: 44, End: 51, Text: "Tuesday"},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
 }
 
 func Test_MatchEmail(t *testing.T) {
 	str := "I am <foo@bar.com> and testing to send to dev@test"
-	actualMatch := MatchEmail()(str)
-	expectedMatch := Match{
-		Template: "I am <%s> and testing to send to dev@test",
-		Patterns: []string{"foo@bar.com"},
+	actualSpans := MatchEmail()(str)
+	expectedSpans := []Span{
+		{Start: 6, End: 17, Text: "foo@bar.com"},
 	}
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
 
 	str = "I am <foo@bar.com> and testing to send to john@doe.io"
-	actualMatch = MatchEmail()(str)
-	expectedMatch = Match{
-		Template: "I am <%s> and testing to send to %s",
-		Patterns: []string{"foo@bar.com", "john@doe.io"},
+	actualSpans = MatchEmail()(str)
+	expectedSpans = []Span{
+		{Start: 6, End: 17, Text: "foo@bar.com"},
+		{Start: 42, End: 53, Text: "john@doe.io"},
+	}
+	assert.Equal(t, expectedSpans, actualSpans)
+}
+
+func Test_MatcherFuncSpans(t *testing.T) {
+	str := "cpu 95% on Skydome, cpu 5% on Skydome"
+	matcher := MatcherFunc(func(str string) Match {
+		return Match{Template: "cpu 95% on %s, cpu 5% on %s", Patterns: []string{"Skydome", "Skydome"}}
+	})
+
+	actualSpans := matcher.Spans(str)
+	expectedSpans := []Span{
+		{Start: 11, End: 18, Text: "Skydome"},
+		{Start: 30, End: 37, Text: "Skydome"},
 	}
-	assert.Equal(t, expectedMatch, actualMatch)
+	assert.Equal(t, expectedSpans, actualSpans)
+
+	matcher = MatcherFunc(func(str string) Match {
+		return Match{Template: "something else %s", Patterns: []string{"Skydome"}}
+	})
+
+	assert.Empty(t, matcher.Spans(str))
 }
 
 func Test_findPatternMatchIndexes(t *testing.T) {
@@ -0,0 +1,125 @@
+package marker
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	ipv4Pattern     = `\d{1,3}(?:\.\d{1,3}){3}`
+	ipv6Pattern     = `(?:[0-9A-Fa-f]{0,4}:){2,7}(?:` + ipv4Pattern + `|[0-9A-Fa-f]{0,4})`
+	zonePattern     = `(?:%[0-9A-Za-z_.-]+)?`
+	hostnamePattern = `(?:[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?\.)*[A-Za-z](?:[A-Za-z0-9-]*[A-Za-z0-9])?`
+	portPattern     = `:\d{1,5}`
+)
+
+var (
+	ipv4Regexp     = regexp.MustCompile(ipv4Pattern)
+	ipv6Regexp     = regexp.MustCompile(ipv6Pattern + zonePattern)
+	ipv4CIDRRegexp = regexp.MustCompile(ipv4Pattern + `/\d{1,2}`)
+	ipv6CIDRRegexp = regexp.MustCompile(ipv6Pattern + `/\d{1,3}`)
+	hostPortRegexp = regexp.MustCompile(`(?:\[` + ipv6Pattern + zonePattern + `\]|` + ipv4Pattern + `|` + hostnamePattern + `)` + portPattern)
+)
+
+// sourceExtensions are the extensions of source files, which look like top level domains in locations such as main.go:12
+var sourceExtensions = map[string]bool{
+	"go": true, "py": true, "rb": true, "rs": true, "js": true, "ts": true, "java": true, "kt": true, "cs": true,
+	"cpp": true, "cc": true, "hpp": true, "php": true, "swift": true, "scala": true, "sh": true,
+}
+
+// MatchIPv4 creates a SpanMatcherFunc that matches IPv4 addresses such as 192.168.0.1.
+// Numbers that are parts of longer tokens, such as the version v1.2.3.4, are not matched.
+func MatchIPv4() SpanMatcherFunc {
+	return matchTokens(ipv4Regexp, ".", ".", validIPv4)
+}
+
+// MatchIPv6 creates a SpanMatcherFunc that matches IPv6 addresses in full, compressed and IPv4-mapped forms,
+// with their zones such as fe80::1%eth0
+func MatchIPv6() SpanMatcherFunc {
+	return matchTokens(ipv6Regexp, ".:", ".:", validIPv6)
+}
+
+// MatchIP creates a SpanMatcherFunc that matches both IPv4 and IPv6 addresses.
+// The IPv4 part of an IPv4-mapped IPv6 address is matched as part of the IPv6 address only.
+func MatchIP() SpanMatcherFunc {
+	ipv6, ipv4 := MatchIPv6(), MatchIPv4()
+	return func(str string) []Span {
+		return mergeSpans(ipv6(str), ipv4(str))
+	}
+}
+
+// MatchCIDR creates a SpanMatcherFunc that matches IPv4 and IPv6 CIDR blocks such as 10.0.0.0/8 and 2001:db8::/32
+func MatchCIDR() SpanMatcherFunc {
+	valid := func(token string) string {
+		if _, _, err := net.ParseCIDR(token); err != nil {
+			return ""
+		}
+		return token
+	}
+	ipv6 := matchTokens(ipv6CIDRRegexp, ".:", ".", valid)
+	ipv4 := matchTokens(ipv4CIDRRegexp, ".", ".", valid)
+	return func(str string) []Span {
+		return mergeSpans(ipv6(str), ipv4(str))
+	}
+}
+
+// MatchHostPort creates a SpanMatcherFunc that matches host:port pairs such as localhost:8080, 10.0.0.1:443 and
+// [::1]:80. Host names must be localhost or have a top level domain, so that source locations such as main.go:12
+// and times such as 12:30 are not matched.
+func MatchHostPort() SpanMatcherFunc {
+	return matchTokens(hostPortRegexp, ".-", ".", validHostPort)
+}
+
+func validIPv4(token string) string {
+	if ip := net.ParseIP(token); ip == nil || ip.To4() == nil {
+		return ""
+	}
+	return token
+}
+
+// validIPv6 returns the IPv6 address at the beginning of given token. The colon that ends a token such as ::1:
+// is dropped, as it is more likely to be punctuation than a part of the address.
+func validIPv6(token string) string {
+	if strings.HasSuffix(token, ":") && !strings.HasSuffix(token, "::") {
+		token = token[:len(token)-1]
+	}
+	address := token
+	if zone := strings.IndexByte(address, '%'); zone >= 0 {
+		address = address[:zone]
+	}
+	// the unspecified address :: alone is more likely to be punctuation, as in std::vector
+	if !strings.Contains(address, ":") || strings.Trim(address, ":") == "" || net.ParseIP(address) == nil {
+		return ""
+	}
+	return token
+}
+
+func validHostPort(token string) string {
+	host, port, err := net.SplitHostPort(token)
+	if err != nil {
+		return ""
+	}
+	if number, err := strconv.Atoi(port); err != nil || number > 65535 {
+		return ""
+	}
+	if strings.HasPrefix(token, "[") {
+		if validIPv6(host) != host {
+			return ""
+		}
+		return token
+	}
+	if host == "localhost" || validIPv4(host) != "" {
+		return token
+	}
+	dot := strings.LastIndexByte(host, '.')
+	if dot < 0 {
+		return ""
+	}
+	tld := strings.ToLower(host[dot+1:])
+	if len(tld) < 2 || strings.Trim(tld, "abcdefghijklmnopqrstuvwxyz") != "" || sourceExtensions[tld] {
+		return ""
+	}
+	return token
+}
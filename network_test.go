@@ -0,0 +1,72 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatchIPv4(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "connection from 192.168.0.1 accepted", expected: []string{"192.168.0.1"}},
+		{str: "peers 10.0.0.1,10.0.0.2 and 127.0.0.1.", expected: []string{"10.0.0.1", "10.0.0.2", "127.0.0.1"}},
+		{str: "dial 10.0.0.1:8080 (range 10.0.0.1-10.0.0.9)", expected: []string{"10.0.0.1", "10.0.0.1", "10.0.0.9"}},
+		{str: "running v1.2.3.4 of build 1.2.3.4.5", expected: nil},
+		{str: "invalid 256.1.1.1 and 1.2.3.999", expected: nil},
+		{str: "x1.2.3.4 1.2.3.4x _1.2.3.4", expected: nil},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchIPv4(), testCase.str), testCase.str)
+	}
+}
+
+func Test_MatchIPv6(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "listening on ::1 and 2001:db8::8a2e:370:7334", expected: []string{"::1", "2001:db8::8a2e:370:7334"}},
+		{str: "full 2001:0db8:0000:0000:0000:ff00:0042:8329 address", expected: []string{"2001:0db8:0000:0000:0000:ff00:0042:8329"}},
+		{str: "link fe80::1ff:fe23:4567:890a%eth0 up", expected: []string{"fe80::1ff:fe23:4567:890a%eth0"}},
+		{str: "mapped ::ffff:192.0.2.128 peer", expected: []string{"::ffff:192.0.2.128"}},
+		{str: "dial [::1]:8080: refused by ::1: reset", expected: []string{"::1", "::1"}},
+		{str: "at 12:30:45 from 00:1a:2b:3c:4d:5e in std::vector and ::", expected: nil},
+		{str: "invalid 2001:db8::1::2 and 1:2:3:4:5:6:7:8:9", expected: nil},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchIPv6(), testCase.str), testCase.str)
+	}
+}
+
+func Test_MatchIP(t *testing.T) {
+	str := "from 10.0.0.1 via ::ffff:192.0.2.128 to 2001:db8::1"
+	assert.Equal(t, []string{"10.0.0.1", "::ffff:192.0.2.128", "2001:db8::1"}, spanTexts(t, MatchIP(), str))
+}
+
+func Test_MatchCIDR(t *testing.T) {
+	str := "allow 10.0.0.0/8, 192.168.1.0/24 and 2001:db8::/32 but not 10.0.0.0/33, 1.2.3.4 or v10.0.0.0/8"
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.0/24", "2001:db8::/32"}, spanTexts(t, MatchCIDR(), str))
+}
+
+func Test_MatchHostPort(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "listening on localhost:8080", expected: []string{"localhost:8080"}},
+		{str: "dial tcp 10.0.0.1:443: i/o timeout", expected: []string{"10.0.0.1:443"}},
+		{str: "upstream [2001:db8::1]:80 and [fe80::1%eth0]:22", expected: []string{"[2001:db8::1]:80", "[fe80::1%eth0]:22"}},
+		{str: "GET http://api.example.com:8443/v1 from db.internal.example.org:5432.", expected: []string{"api.example.com:8443", "db.internal.example.org:5432"}},
+		{str: "main.go:12 at 12:30 with port 99999 on localhost:99999 and host:80", expected: nil},
+		{str: "v1.2.3.4:80 and 10.0.0.1:80.5", expected: nil},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchHostPort(), testCase.str), testCase.str)
+	}
+}
@@ -0,0 +1,69 @@
+package marker
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// numberRegexp matches the candidates of numbers: hex, octal and binary literals, then decimal numbers with
+// digit separators, fractions and exponents
+var numberRegexp = regexp.MustCompile(`[-+]?(?:` +
+	`0[xX][0-9a-fA-F](?:_?[0-9a-fA-F])*|0[oO][0-7](?:_?[0-7])*|0[bB][01](?:_?[01])*|` +
+	`(?:(?:\d{1,3}(?:,\d{3})+|\d(?:_?\d)*)(?:\.\d(?:_?\d)*)?|\.\d(?:_?\d)*)(?:[eE][-+]?\d+)?)`)
+
+// numberJoiners are the characters that join a number to the text next to it into a longer token,
+// as in timestamps, versions and paths
+const numberJoiners = ".:-/"
+
+// CommonUnits are the units of durations, sizes, percentages and rates that commonly follow numbers in logs
+var CommonUnits = []string{
+	"ns", "us", "µs", "ms", "s", "m", "h", "%",
+	"B", "KB", "MB", "GB", "TB", "KiB", "MiB", "GiB", "TiB",
+	"req/s", "ops/s", "rps", "/s",
+}
+
+// MatchNumber creates a SpanMatcherFunc that matches numbers such as 42, -3.14, 6.02e23, 0x1F, 0o17, 0b1010,
+// 1_000_000 and 1,000,000. A unit of given units right after a number, as in 250ms or 12.5MiB, is matched along with
+// it, like the CommonUnits in MatchNumber(CommonUnits...). Numbers that are parts of longer tokens such as
+// identifiers, timestamps and versions are not matched.
+func MatchNumber(units ...string) SpanMatcherFunc {
+	// longer units are tried first, so that ms is not matched as m
+	sorted := append([]string(nil), units...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	return func(str string) []Span {
+		var spans []Span
+		for _, index := range numberRegexp.FindAllStringIndex(str, -1) {
+			start, end := index[0], index[1]
+			if start > 0 && (isWordByte(str[start-1]) || strings.IndexByte(numberJoiners, str[start-1]) >= 0) {
+				continue
+			}
+			for _, unit := range sorted {
+				if unit != "" && strings.HasPrefix(str[end:], unit) && tokenEndsAt(str, end+len(unit)) {
+					end += len(unit)
+					break
+				}
+			}
+			if !tokenEndsAt(str, end) {
+				continue
+			}
+			spans = append(spans, Span{Start: start, End: end, Text: str[start:end]})
+		}
+		return spans
+	}
+}
+
+// tokenEndsAt reports whether a number token ends at given offset of the string
+func tokenEndsAt(str string, offset int) bool {
+	if offset == len(str) {
+		return true
+	}
+	if isWordByte(str[offset]) {
+		return false
+	}
+	joined := strings.IndexByte(numberJoiners, str[offset]) >= 0
+	return !joined || offset+1 == len(str) || !isWordByte(str[offset+1])
+}
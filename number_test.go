@@ -0,0 +1,44 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatchNumber(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "count=42 delta=-7 gain=+3", expected: []string{"42", "-7", "+3"}},
+		{str: "pi is 3.14, half is .5 and avogadro 6.02e23 or 1.6E-19.", expected: []string{"3.14", ".5", "6.02e23", "1.6E-19"}},
+		{str: "flags 0x1F 0XdeadBEEF 0o17 0b1010 0b1_0", expected: []string{"0x1F", "0XdeadBEEF", "0o17", "0b1010", "0b1_0"}},
+		{str: "total 1_000_000 or 1,000,000.50 from 1,2,3", expected: []string{"1_000_000", "1,000,000.50", "1", "2", "3"}},
+		{str: "(5) [6] {7} x=8; 9!", expected: []string{"5", "6", "7", "8", "9"}},
+		{str: "user123 abc-123 id_7 2019-10-18T10:00:00 v1.2.3 10.0.0.1 /api/v2/items/77 3rd 1e 0x", expected: nil},
+		{str: "took 250ms and 12.5MiB at 99.9%", expected: []string{"99.9"}},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchNumber(), testCase.str), testCase.str)
+	}
+}
+
+func Test_MatchNumberUnits(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "took 250ms and 12.5MiB at 99.9% for 1500req/s", expected: []string{"250ms", "12.5MiB", "99.9%", "1500req/s"}},
+		{str: "timeout 30s, retry 5m, wait 2h, 10µs and 1_024B.", expected: []string{"30s", "5m", "2h", "10µs", "1_024B"}},
+		{str: "3 seconds, 5 MiB, 7mins and 8%x", expected: []string{"3", "5", "8"}},
+		{str: "2019-10-18 10:00:00 at v1.2s", expected: nil},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchNumber(CommonUnits...), testCase.str), testCase.str)
+	}
+
+	assert.Equal(t, []string{"3items", "4"}, spanTexts(t, MatchNumber("items", "item"), "3items 4 things"))
+}
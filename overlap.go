@@ -0,0 +1,140 @@
+package marker
+
+import (
+	"sort"
+)
+
+// OverlapPolicy decides how overlapping spans found by different rules are marked
+type OverlapPolicy int
+
+const (
+	// OverlapSplit splits overlapping spans into segments and layers the styles of all spans covering each segment,
+	// inner spans on top of outer ones
+	OverlapSplit OverlapPolicy = iota
+	// OverlapFirstRule keeps the span of the rule added first and drops the spans of later rules overlapping it
+	OverlapFirstRule
+	// OverlapLongest keeps the longest span and drops the shorter spans overlapping it
+	OverlapLongest
+	// OverlapPriority keeps the span of the rule with the highest Priority and drops the spans overlapping it
+	OverlapPriority
+)
+
+// markedSpan is a span of visible text along with the rule that found it and its style
+type markedSpan struct {
+	Span
+	index int
+	rule  MarkRule
+	style Style
+}
+
+// segment is a part of visible text along with the spans covering it, outermost span first
+type segment struct {
+	from   int
+	to     int
+	layers []markedSpan
+}
+
+// resolve returns the non overlapping segments to be marked ordered by their position
+func (p OverlapPolicy) resolve(spans []markedSpan) []segment {
+	switch p {
+	case OverlapFirstRule:
+		return keepFirst(spans, func(a, b markedSpan) bool {
+			return a.index < b.index
+		})
+	case OverlapLongest:
+		return keepFirst(spans, func(a, b markedSpan) bool {
+			return a.End-a.Start > b.End-b.Start
+		})
+	case OverlapPriority:
+		return keepFirst(spans, func(a, b markedSpan) bool {
+			return a.rule.Priority > b.rule.Priority
+		})
+	default:
+		return split(spans)
+	}
+}
+
+// keepFirst sorts the spans by precedence and keeps the spans that does not overlap any span preceding them.
+// Ties are broken by the order of rules and then by the position of spans.
+func keepFirst(spans []markedSpan, precedes func(a, b markedSpan) bool) []segment {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if precedes(spans[i], spans[j]) {
+			return true
+		}
+		if precedes(spans[j], spans[i]) {
+			return false
+		}
+		if spans[i].index != spans[j].index {
+			return spans[i].index < spans[j].index
+		}
+		return spans[i].Start < spans[j].Start
+	})
+
+	var kept []markedSpan
+	for _, span := range spans {
+		if !overlapsAny(span, kept) {
+			kept = append(kept, span)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Start < kept[j].Start })
+
+	segments := make([]segment, len(kept))
+	for i, span := range kept {
+		segments[i] = segment{from: span.Start, to: span.End, layers: kept[i : i+1]}
+	}
+	return segments
+}
+
+// split cuts the spans at every boundary and layers all spans covering each segment
+func split(spans []markedSpan) []segment {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].Start != spans[j].Start {
+			return spans[i].Start < spans[j].Start
+		}
+		if spans[i].End != spans[j].End {
+			return spans[i].End > spans[j].End
+		}
+		return spans[i].index < spans[j].index
+	})
+
+	boundaries := make([]int, 0, 2*len(spans))
+	for _, span := range spans {
+		boundaries = append(boundaries, span.Start, span.End)
+	}
+	sort.Ints(boundaries)
+
+	// sweep the boundaries once, keeping the spans covering the current segment in their sorted order
+	var segments []segment
+	var active []markedSpan
+	next := 0
+	for i := 1; i < len(boundaries); i++ {
+		from, to := boundaries[i-1], boundaries[i]
+		if from == to {
+			continue
+		}
+		kept := active[:0]
+		for _, span := range active {
+			if span.End > from {
+				kept = append(kept, span)
+			}
+		}
+		active = kept
+		for ; next < len(spans) && spans[next].Start <= from; next++ {
+			active = append(active, spans[next])
+		}
+		if len(active) > 0 {
+			layers := append([]markedSpan(nil), active...)
+			segments = append(segments, segment{from: from, to: to, layers: layers})
+		}
+	}
+	return segments
+}
+
+func overlapsAny(span markedSpan, spans []markedSpan) bool {
+	for _, other := range spans {
+		if span.Start < other.End && other.Start < span.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package marker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OverlapPolicy(t *testing.T) {
+	blueFg := color.New(color.FgBlue)
+	blueFg.EnableColor()
+	blue := blueFg.SprintFunc()
+
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+
+	str := "mail john@example.com for an example"
+	emailRule := MarkRule{Matcher: MatchEmail(), Style: FromColor(blueFg)}
+	exampleRule := MarkRule{Matcher: MatchAll("example"), Style: FromColor(redFg)}
+
+	tests := []struct {
+		name     string
+		policy   OverlapPolicy
+		rules    []MarkRule
+		expected string
+	}{
+		{
+			name:     "Split",
+			policy:   OverlapSplit,
+			rules:    []MarkRule{emailRule, exampleRule},
+			expected: fmt.Sprintf("mail \x1b[34mjohn@%s\x1b[34m.com\x1b[0m for an %s", red("example"), red("example")),
+		},
+		{
+			name:     "Split reversed",
+			policy:   OverlapSplit,
+			rules:    []MarkRule{exampleRule, emailRule},
+			expected: fmt.Sprintf("mail \x1b[34mjohn@%s\x1b[34m.com\x1b[0m for an %s", red("example"), red("example")),
+		},
+		{
+			name:     "First rule",
+			policy:   OverlapFirstRule,
+			rules:    []MarkRule{emailRule, exampleRule},
+			expected: fmt.Sprintf("mail %s for an %s", blue("john@example.com"), red("example")),
+		},
+		{
+			name:     "First rule reversed",
+			policy:   OverlapFirstRule,
+			rules:    []MarkRule{exampleRule, emailRule},
+			expected: fmt.Sprintf("mail john@%s.com for an %s", red("example"), red("example")),
+		},
+		{
+			name:     "Longest",
+			policy:   OverlapLongest,
+			rules:    []MarkRule{exampleRule, emailRule},
+			expected: fmt.Sprintf("mail %s for an %s", blue("john@example.com"), red("example")),
+		},
+		{
+			name:   "Priority",
+			policy: OverlapPriority,
+			rules: []MarkRule{
+				{Matcher: MatchEmail(), Style: FromColor(blueFg)},
+				{Matcher: MatchAll("example"), Style: FromColor(redFg), Priority: 1},
+			},
+			expected: fmt.Sprintf("mail john@%s.com for an %s", red("example"), red("example")),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := markRules(str, testCase.rules, testCase.policy)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
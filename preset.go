@@ -0,0 +1,187 @@
+package marker
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// presets are the built-in rule sets by name. Rules are created on each call, so callers can change them freely.
+var presets = map[string]func() []MarkRule{
+	"go-log":   goLogRules,
+	"go-test":  goTestRules,
+	"combined": combinedLogRules,
+	"nginx":    combinedLogRules,
+	"apache":   combinedLogRules,
+	"syslog":   syslogRules,
+	"docker":   containerLogRules,
+	"kubectl":  containerLogRules,
+	"logfmt":   logfmtRules,
+}
+
+// Preset returns the rules of a built-in preset for a common log format:
+//
+//	go-log           output of the log package with its default flags
+//	go-test          output of go test -v
+//	combined         access logs in combined log format, also named nginx and apache
+//	syslog           syslog messages as in /var/log/syslog
+//	docker, kubectl  container logs with timestamps, compose service prefixes and pod prefixes
+//	logfmt           key=value pairs as written by logfmt loggers
+func Preset(name string) ([]MarkRule, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, presets are %s", name, strings.Join(PresetNames(), ", "))
+	}
+	return preset(), nil
+}
+
+// PresetNames returns the names of the built-in presets in alphabetical order
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	goLogTimestampRegexp = regexp.MustCompile(`(?m)^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d(?:\.\d+)?`)
+	goSourceRegexp       = regexp.MustCompile(`[\w./-]+\.go:\d+`)
+	levelRegexp          = regexp.MustCompile(`\b(?:TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|CRITICAL|FATAL|PANIC)\b`)
+
+	goTestStatusRegexp  = regexp.MustCompile(`(?m)^\s*--- (?:(?P<pass>PASS)|(?P<fail>FAIL)|(?P<skip>SKIP)): (?P<name>\S+) (?P<duration>\([\d.]+s\))`)
+	goTestRunRegexp     = regexp.MustCompile(`(?m)^=== (?:RUN|PAUSE|CONT|NAME)\s+\S+`)
+	goTestSummaryRegexp = regexp.MustCompile(`(?m)^(?:(?P<pass>PASS|ok)|(?P<fail>FAIL))(?:\s|$)`)
+	goPanicRegexp       = regexp.MustCompile(`(?m)^panic: .*`)
+
+	combinedLogRegexp = regexp.MustCompile(`(?m)^(?P<client>\S+) \S+ (?P<user>\S+) (?P<time>\[[^\]]+\]) ` +
+		`"(?P<method>[A-Z]+) (?P<path>[^ "]+)[^"]*" (?P<status>\d{3}) (?P<size>\d+|-)` +
+		`(?: "(?P<referer>[^"]*)" "(?P<agent>[^"]*)")?`)
+
+	syslogRegexp = regexp.MustCompile(`(?m)^(?P<time>[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d) (?P<host>\S+) ` +
+		`(?P<program>[^:\[\s]+)(?P<pid>\[\d+\])?:`)
+
+	composePrefixRegexp = regexp.MustCompile(`(?m)^[\w.-]+\s+\|`)
+	podPrefixRegexp     = regexp.MustCompile(`(?m)^\[pod/[^\]]+\]`)
+
+	logfmtPairRegexp  = regexp.MustCompile(`(?P<key>[\w.-]+)=(?:"(?:[^"\\]|\\.)*"|\S*)`)
+	logfmtLevelRegexp = regexp.MustCompile(`\b(?:level|lvl|severity)=(?P<level>"?\w+"?)`)
+)
+
+// levelStyle styles the level of a log message such as INFO or error by its severity
+func levelStyle(span Span) Style {
+	switch strings.ToUpper(strings.Trim(span.Text, `"`)) {
+	case "TRACE", "DEBUG":
+		return TextStyle{Attrs: Faint}
+	case "INFO", "NOTICE":
+		return Fg(Green)
+	case "WARN", "WARNING":
+		return Fg(Yellow)
+	case "ERROR", "ERR":
+		return Fg(Red)
+	case "CRITICAL", "FATAL", "PANIC":
+		return Fg(Red).With(Bold)
+	}
+	return nil
+}
+
+// statusStyle styles an HTTP status code by its class
+func statusStyle(span Span) Style {
+	switch {
+	case strings.HasPrefix(span.Text, "2"):
+		return Fg(Green)
+	case strings.HasPrefix(span.Text, "3"):
+		return Fg(Cyan)
+	case strings.HasPrefix(span.Text, "4"):
+		return Fg(Yellow)
+	case strings.HasPrefix(span.Text, "5"):
+		return Fg(Red).With(Bold)
+	}
+	return nil
+}
+
+// groupStyle applies styleFunc only on the spans of given group of a RegexpGroupMatcher
+func groupStyle(group string, styleFunc StyleFunc) StyleFunc {
+	return func(span Span) Style {
+		if span.Meta["group"] != group {
+			return nil
+		}
+		return styleFunc(span)
+	}
+}
+
+func levelRule() MarkRule {
+	return MarkRule{Matcher: MatchRegexp(levelRegexp), StyleFunc: levelStyle}
+}
+
+func goLogRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchRegexp(goLogTimestampRegexp), Style: Fg(Cyan)},
+		{Matcher: MatchRegexp(goSourceRegexp), Style: TextStyle{Attrs: Faint}},
+		{Matcher: MatchBracketSurrounded(), Style: Fg(Blue)},
+		levelRule(),
+	}
+}
+
+func goTestRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchRegexp(goTestRunRegexp), Style: TextStyle{Attrs: Faint}},
+		{Matcher: MatchRegexpGroups(goTestStatusRegexp, map[string]Style{
+			"pass":     Fg(Green).With(Bold),
+			"fail":     Fg(Red).With(Bold),
+			"skip":     Fg(Yellow).With(Bold),
+			"duration": TextStyle{Attrs: Faint},
+		})},
+		{Matcher: MatchRegexpGroups(goTestSummaryRegexp, map[string]Style{
+			"pass": Fg(Green).With(Bold),
+			"fail": Fg(Red).With(Bold),
+		})},
+		{Matcher: MatchRegexp(goSourceRegexp), Style: Fg(Cyan)},
+		{Matcher: MatchRegexp(goPanicRegexp), Style: Fg(Red).With(Bold)},
+	}
+}
+
+func combinedLogRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchRegexpGroups(combinedLogRegexp, map[string]Style{
+			"client":  Fg(Magenta),
+			"time":    Fg(Cyan),
+			"method":  TextStyle{Attrs: Bold},
+			"path":    Fg(Blue),
+			"referer": TextStyle{Attrs: Faint},
+			"agent":   TextStyle{Attrs: Faint},
+		})},
+		{Matcher: MatchRegexpGroups(combinedLogRegexp, map[string]Style{"status": nil}), StyleFunc: groupStyle("status", statusStyle)},
+	}
+}
+
+func syslogRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchRegexpGroups(syslogRegexp, map[string]Style{
+			"time":    Fg(Cyan),
+			"host":    Fg(Magenta),
+			"program": Fg(Blue).With(Bold),
+			"pid":     TextStyle{Attrs: Faint},
+		})},
+		levelRule(),
+	}
+}
+
+func containerLogRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchTimestamp(time.RFC3339Nano), Style: Fg(Cyan)},
+		{Matcher: MatchRegexp(composePrefixRegexp), Style: Fg(Magenta)},
+		{Matcher: MatchRegexp(podPrefixRegexp), Style: Fg(Magenta)},
+		levelRule(),
+	}
+}
+
+func logfmtRules() []MarkRule {
+	return []MarkRule{
+		{Matcher: MatchRegexpGroups(logfmtPairRegexp, map[string]Style{"key": Fg(Cyan)})},
+		{Matcher: MatchRegexpGroups(logfmtLevelRegexp, map[string]Style{"level": nil}), StyleFunc: groupStyle("level", levelStyle)},
+	}
+}
@@ -0,0 +1,98 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Preset(t *testing.T) {
+	tests := []struct {
+		preset   string
+		str      string
+		expected map[string]TextStyle
+	}{
+		{
+			preset: "go-log",
+			str:    "2009/11/10 23:00:00 main.go:12: [api] ERROR connection refused",
+			expected: map[string]TextStyle{
+				"2009/11/10 23:00:00": Fg(Cyan),
+				"main.go:12":          {Attrs: Faint},
+				"[api]":               Fg(Blue),
+				"ERROR":               Fg(Red),
+			},
+		},
+		{
+			preset: "go-test",
+			str:    "=== RUN   TestMark\n    --- FAIL: TestMark/nested (0.01s)\n        marker_test.go:42: wrong\nFAIL\n",
+			expected: map[string]TextStyle{
+				"=== RUN   TestMark": {Attrs: Faint},
+				"FAIL":               Fg(Red).With(Bold),
+				"(0.01s)":            {Attrs: Faint},
+				"marker_test.go:42":  Fg(Cyan),
+			},
+		},
+		{
+			preset: "nginx",
+			str:    `203.0.113.9 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 404 153 "-" "curl/7.64.1"`,
+			expected: map[string]TextStyle{
+				"203.0.113.9":                  Fg(Magenta),
+				"[10/Oct/2000:13:55:36 -0700]": Fg(Cyan),
+				"GET":                          {Attrs: Bold},
+				"/index.html":                  Fg(Blue),
+				"404":                          Fg(Yellow),
+				"-":                            {Attrs: Faint},
+				"curl/7.64.1":                  {Attrs: Faint},
+			},
+		},
+		{
+			preset: "syslog",
+			str:    "Oct  1 22:14:15 web01 sshd[4321]: WARNING too many attempts",
+			expected: map[string]TextStyle{
+				"Oct  1 22:14:15": Fg(Cyan),
+				"web01":           Fg(Magenta),
+				"sshd":            Fg(Blue).With(Bold),
+				"[4321]":          {Attrs: Faint},
+				"WARNING":         Fg(Yellow),
+			},
+		},
+		{
+			preset: "docker",
+			str:    "web_1  | 2019-10-18T10:00:00.123456789Z INFO listening\n",
+			expected: map[string]TextStyle{
+				"web_1  |":                       Fg(Magenta),
+				"2019-10-18T10:00:00.123456789Z": Fg(Cyan),
+				"INFO":                           Fg(Green),
+			},
+		},
+		{
+			preset: "kubectl",
+			str:    "[pod/api-7d9/api] 2019-10-18T10:00:00+03:00 FATAL out of memory",
+			expected: map[string]TextStyle{
+				"[pod/api-7d9/api]":         Fg(Magenta),
+				"2019-10-18T10:00:00+03:00": Fg(Cyan),
+				"FATAL":                     Fg(Red).With(Bold),
+			},
+		},
+		{
+			preset: "logfmt",
+			str:    `ts=2019-10-18 level=error msg="disk full" path=/var`,
+			expected: map[string]TextStyle{
+				"ts":    Fg(Cyan),
+				"level": Fg(Cyan),
+				"error": Fg(Red),
+				"msg":   Fg(Cyan),
+				"path":  Fg(Cyan),
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		rules, err := Preset(testCase.preset)
+		assert.Nil(t, err, testCase.preset)
+		assert.Equal(t, testCase.expected, styledTexts(testCase.str, rules), testCase.preset)
+	}
+
+	_, err := Preset("iis")
+	assert.EqualError(t, err, `unknown preset "iis", presets are apache, combined, docker, go-log, go-test, kubectl, logfmt, nginx, syslog`)
+}
@@ -18,9 +18,11 @@ const (
 	year          = "[0-9]{4}"                                    // 2006
 	timezone      = "[A-Z]{3}"                                    // MST
 	numericZone   = "-[0-9]{4}"                                   // -0300
+	rfc3339Zone   = "(Z|[+-]" + hhmm + ")"                        // Z or -03:00
 	milli         = ".[0-9]{3}"
 	micro         = ".[0-9]{6}"
 	nano          = ".[0-9]{9}"
+	fraction      = "(\\.[0-9]{1,9})?" // .999999999 without its trailing zeros
 )
 
 var (
@@ -32,8 +34,8 @@ var (
 	RFC850Regexp      = regexp.MustCompile(fmt.Sprintf("%s, %s-%s-[0-9]{2} %s %s", weekdays, daysWithZero, monthsAbv, hhmmss, timezone))
 	RFC1123Regexp     = regexp.MustCompile(fmt.Sprintf("%s, %s %s %s %s %s", weekdaysAbv, daysWithZero, monthsAbv, year, hhmmss, timezone))
 	RFC1123ZRegexp    = regexp.MustCompile(fmt.Sprintf("%s, %s %s %s %s %s", weekdaysAbv, daysWithZero, monthsAbv, year, hhmmss, numericZone))
-	RFC3339Regexp     = regexp.MustCompile(fmt.Sprintf("%s-%s-%sT%sZ%s", year, numericmonths, daysWithZero, hhmmss, hhmm))
-	RFC3339NanoRegexp = regexp.MustCompile(fmt.Sprintf("%s-%s-%sT%s%sZ%s", year, numericmonths, daysWithZero, hhmmss, nano, hhmm))
+	RFC3339Regexp     = regexp.MustCompile(fmt.Sprintf("%s-%s-%sT%s%s", year, numericmonths, daysWithZero, hhmmss, rfc3339Zone))
+	RFC3339NanoRegexp = regexp.MustCompile(fmt.Sprintf("%s-%s-%sT%s%s%s", year, numericmonths, daysWithZero, hhmmss, fraction, rfc3339Zone))
 	KitchenRegexp     = regexp.MustCompile("(([0-1]?[0-9]|2[0-3]):[0-5][0-9][P|A]M)")
 	StampRegexp       = regexp.MustCompile(fmt.Sprintf("%s %s %s(\\s|$)", monthsAbv, days, hhmmss))
 	StampMilliRegexp  = regexp.MustCompile(fmt.Sprintf("%s %s %s%s", monthsAbv, days, hhmmss, milli))
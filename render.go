@@ -0,0 +1,161 @@
+package marker
+
+// Renderer renders marked text for a target such as a terminal or a web page
+type Renderer interface {
+	Render(marked Marked) string
+}
+
+// Marked is the visible text of a marked string along with its styled segments
+type Marked struct {
+	Text     string
+	Segments []Segment
+	escapes  []escape
+	segments []segment
+}
+
+// Segment is a part of the visible text covered by one or more spans.
+// Styles and Spans are ordered from the outermost span to the innermost one.
+type Segment struct {
+	Start  int
+	End    int
+	Text   string
+	Styles []Style
+	Spans  []Span
+}
+
+// Style returns the styles of the segment layered into one
+func (s Segment) Style() TextStyle {
+	return composeStyles(TextStyle{}, s.Styles...)
+}
+
+func newMarked(visible string, escapes []escape, segments []segment) Marked {
+	marked := Marked{Text: visible, escapes: escapes, segments: segments}
+	for _, segment := range segments {
+		exported := Segment{Start: segment.from, End: segment.to, Text: visible[segment.from:segment.to]}
+		for _, layer := range segment.layers {
+			exported.Styles = append(exported.Styles, layer.style)
+			exported.Spans = append(exported.Spans, layer.Span)
+		}
+		marked.Segments = append(marked.Segments, exported)
+	}
+	return marked
+}
+
+// run is a part of the visible text drawn with a single style
+type run struct {
+	text  string
+	style TextStyle
+}
+
+// runs splits the visible text into parts drawn with a single style.
+// The styles of segments are layered on top of the styles set by the escapes of the original text.
+func (m Marked) runs() []run {
+	var runs []run
+	var base TextStyle
+	escapes := m.escapes
+	segment := 0
+	offset := 0
+	for offset < len(m.Text) || len(escapes) > 0 {
+		for len(escapes) > 0 && escapes[0].offset == offset {
+			if parameters, ok := sgrParameters(escapes[0].sequence); ok {
+				base = styleFromSGR(base, parameters)
+			}
+			escapes = escapes[1:]
+		}
+		if offset == len(m.Text) {
+			break
+		}
+		for segment < len(m.Segments) && m.Segments[segment].End <= offset {
+			segment++
+		}
+
+		next, style := len(m.Text), base
+		if len(escapes) > 0 {
+			next = escapes[0].offset
+		}
+		if segment < len(m.Segments) {
+			if current := m.Segments[segment]; current.Start <= offset {
+				next = min(next, current.End)
+				style = composeStyles(base, current.Styles...)
+			} else {
+				next = min(next, current.Start)
+			}
+		}
+
+		if n := len(runs); n > 0 && runs[n-1].style == style {
+			runs[n-1].text += m.Text[offset:next]
+		} else {
+			runs = append(runs, run{text: m.Text[offset:next], style: style})
+		}
+		offset = next
+	}
+	return runs
+}
+
+// ANSIRenderer renders marked text with ANSI escape sequences for terminals.
+// Escape sequences of the original text are kept as they are, except their colors are degraded to Depth too.
+// With DepthNone the text is rendered without any escape sequences.
+type ANSIRenderer struct {
+	Depth ColorDepth
+}
+
+// Render returns the marked text with escape sequences
+func (r ANSIRenderer) Render(marked Marked) string {
+	switch r.Depth {
+	case DepthTrueColor:
+		return markSegments(marked.Text, marked.escapes, marked.segments)
+	case DepthNone:
+		return marked.Text
+	}
+
+	escapes := make([]escape, len(marked.escapes))
+	for i, e := range marked.escapes {
+		escapes[i] = escape{offset: e.offset, sequence: degradeSGR(e.sequence, r.Depth)}
+	}
+	segments := make([]segment, len(marked.segments))
+	for i, s := range marked.segments {
+		segments[i] = segment{from: s.from, to: s.to, layers: make([]markedSpan, len(s.layers))}
+		for j, layer := range s.layers {
+			layer.style = degradeStyle(layer.style, r.Depth)
+			segments[i].layers[j] = layer
+		}
+	}
+	return markSegments(marked.Text, escapes, segments)
+}
+
+// PlainRenderer renders marked text without any styling.
+// Escape sequences of the original text are removed as well.
+type PlainRenderer struct{}
+
+// Render returns the visible text
+func (PlainRenderer) Render(marked Marked) string {
+	return marked.Text
+}
+
+// composeStyles layers given styles on top of base.
+// Colors of upper styles replace the ones below them and attributes are added together.
+func composeStyles(base TextStyle, styles ...Style) TextStyle {
+	for _, style := range styles {
+		if style == nil {
+			continue
+		}
+		if fg := style.Foreground(); !fg.IsDefault() {
+			base.Fg = fg
+		}
+		if bg := style.Background(); !bg.IsDefault() {
+			base.Bg = bg
+		}
+		if ul := underlineColor(style); !ul.IsDefault() {
+			base.Ul = ul
+		}
+		base.Attrs |= style.Attributes()
+	}
+	return base
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
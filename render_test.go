@@ -0,0 +1,60 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var renderTestRules = []MarkRule{
+	{Matcher: MatchBracketSurrounded(), Style: Fg(Blue)},
+	{Matcher: MatchAll("ERROR"), Style: Fg(Red).With(Bold)},
+}
+
+func Test_Render(t *testing.T) {
+	str := "[ERROR] disk <sda> is full"
+
+	ansi := Render(str, ANSIRenderer{}, renderTestRules...)
+	assert.Equal(t, "\x1b[34m[\x1b[1;31mERROR\x1b[0m\x1b[34m]\x1b[0m disk <sda> is full", ansi)
+
+	plain := Render(str, PlainRenderer{}, renderTestRules...)
+	assert.Equal(t, str, plain)
+
+	plain = Render("\x1b[32m"+str+"\x1b[0m", PlainRenderer{}, renderTestRules...)
+	assert.Equal(t, str, plain)
+}
+
+func Test_MarkedSegments(t *testing.T) {
+	marked := markSegmentsOf("[ERROR] disk is full", renderTestRules, OverlapSplit)
+
+	expected := []Segment{
+		{Start: 0, End: 1, Text: "[", Styles: []Style{Fg(Blue)}, Spans: []Span{{Start: 0, End: 7, Text: "[ERROR]"}}},
+		{Start: 1, End: 6, Text: "ERROR", Styles: []Style{Fg(Blue), Fg(Red).With(Bold)}, Spans: []Span{
+			{Start: 0, End: 7, Text: "[ERROR]"},
+			{Start: 1, End: 6, Text: "ERROR"},
+		}},
+		{Start: 6, End: 7, Text: "]", Styles: []Style{Fg(Blue)}, Spans: []Span{{Start: 0, End: 7, Text: "[ERROR]"}}},
+	}
+	assert.Equal(t, "[ERROR] disk is full", marked.Text)
+	assert.Equal(t, expected, marked.Segments)
+	assert.Equal(t, Fg(Red).With(Bold), marked.Segments[1].Style())
+}
+
+func Test_MarkedRuns(t *testing.T) {
+	marked := markSegmentsOf("\x1b[4m[ERROR]\x1b[0m disk \x1b[42mis\x1b[0m full", renderTestRules, OverlapSplit)
+
+	expected := []run{
+		{text: "[", style: Fg(Blue).With(Underline)},
+		{text: "ERROR", style: Fg(Red).With(Bold | Underline)},
+		{text: "]", style: Fg(Blue).With(Underline)},
+		{text: " disk ", style: TextStyle{}},
+		{text: "is", style: Bg(Green)},
+		{text: " full", style: TextStyle{}},
+	}
+	assert.Equal(t, expected, marked.runs())
+}
+
+func Test_composeStyles(t *testing.T) {
+	composed := composeStyles(Bg(White), Fg(Blue).With(Bold), nil, Fg(Red).With(Italic))
+	assert.Equal(t, Fg(Red).On(White).With(Bold|Italic), composed)
+}
@@ -0,0 +1,520 @@
+package marker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleFormat is the format of a rule file
+type RuleFormat int
+
+// Supported rule file formats
+const (
+	YAML RuleFormat = iota
+	JSON
+	TOML
+)
+
+// RuleError is an error in a rule file, found at Line of File when they are known
+type RuleError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	case e.File != "":
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return e.Err.Error()
+}
+
+// timestampLayoutNames are the names of the time layouts that MatchTimestamp supports
+var timestampLayoutNames = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// ruleSpec is a rule as it is written in a rule file, with the lines of the rule and its fields
+type ruleSpec struct {
+	match    string
+	text     string
+	pattern  string
+	n        int
+	open     string
+	close    string
+	layout   string
+	style    string
+	priority int
+
+	line  int
+	lines map[string]int
+}
+
+// LoadRulesFile reads the rules in given file, whose format is chosen by its extension:
+// .yaml or .yml for YAML, .json for JSON and .toml for TOML
+func LoadRulesFile(path string) ([]MarkRule, error) {
+	var format RuleFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = YAML
+	case ".json":
+		format = JSON
+	case ".toml":
+		format = TOML
+	default:
+		return nil, &RuleError{File: path, Err: errors.New("unknown rule file format")}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := parseRules(data, format)
+	if ruleErr, ok := err.(*RuleError); ok {
+		ruleErr.File = path
+	}
+	return rules, err
+}
+
+// LoadRules reads rules in given format. Rules are listed under the "rules" key, such as in YAML:
+//
+//	rules:
+//	  - match: regexp
+//	    pattern: '\d+'
+//	    style: bold red on white
+//
+// "match" is the type of matcher, which takes its arguments from the other fields of the rule:
+// all (text), n (text, n), regexp (pattern), surrounded (open, close), timestamp (layout, such as RFC3339 or a
+// layout of the time package), email and days. "style" is parsed with ParseStyle and "priority" sets the priority
+// of the rule. Errors are returned as *RuleError with the line of the invalid field or rule.
+func LoadRules(r io.Reader, format RuleFormat) ([]MarkRule, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRules(data, format)
+}
+
+func parseRules(data []byte, format RuleFormat) ([]MarkRule, error) {
+	var specs []ruleSpec
+	var err error
+	switch format {
+	case YAML:
+		specs, err = yamlRuleSpecs(data)
+	case JSON:
+		specs, err = jsonRuleSpecs(data)
+	case TOML:
+		specs, err = tomlRuleSpecs(data)
+	default:
+		err = &RuleError{Err: fmt.Errorf("unknown rule file format %d", format)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]MarkRule, 0, len(specs))
+	for _, spec := range specs {
+		rule, err := spec.markRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func yamlRuleSpecs(data []byte) ([]ruleSpec, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, parserError(err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &RuleError{Line: root.Line, Err: errors.New("rules must be listed under the rules key")}
+	}
+
+	var specs []ruleSpec
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "rules" {
+			return nil, &RuleError{Line: key.Line, Err: fmt.Errorf("unknown key %q", key.Value)}
+		}
+		if value.Kind != yaml.SequenceNode {
+			return nil, &RuleError{Line: value.Line, Err: errors.New("rules must be a list")}
+		}
+		for _, item := range value.Content {
+			if item.Kind != yaml.MappingNode {
+				return nil, &RuleError{Line: item.Line, Err: errors.New("rule must be a mapping")}
+			}
+			spec := ruleSpec{line: item.Line, lines: make(map[string]int)}
+			for j := 0; j+1 < len(item.Content); j += 2 {
+				var field interface{}
+				if err := item.Content[j+1].Decode(&field); err != nil {
+					return nil, &RuleError{Line: item.Content[j+1].Line, Err: err}
+				}
+				name := item.Content[j].Value
+				spec.lines[name] = item.Content[j].Line
+				if err := spec.set(name, field); err != nil {
+					return nil, err
+				}
+			}
+			specs = append(specs, spec)
+		}
+	}
+	return specs, nil
+}
+
+// jsonRuleSpecs reads JSON rules with the YAML parser, as JSON is a subset of YAML that keeps the lines of fields,
+// after checking that the data is valid JSON. Tabs are replaced with spaces for the YAML parser, which is safe as
+// they can only appear between the tokens of valid JSON.
+func jsonRuleSpecs(data []byte) ([]ruleSpec, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		line := 0
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			line = 1 + bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+		}
+		return nil, &RuleError{Line: line, Err: err}
+	}
+	return yamlRuleSpecs(bytes.Replace(data, []byte("\t"), []byte(" "), -1))
+}
+
+func tomlRuleSpecs(data []byte) ([]ruleSpec, error) {
+	var document map[string]interface{}
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		return nil, parserError(err)
+	}
+
+	ruleLines := tomlRuleLines(data)
+	var specs []ruleSpec
+	for key, value := range document {
+		if key != "rules" {
+			return nil, &RuleError{Err: fmt.Errorf("unknown key %q", key)}
+		}
+		rules, ok := tomlTables(value)
+		if !ok {
+			return nil, &RuleError{Err: errors.New("rules must be an array of tables")}
+		}
+		for i, rule := range rules {
+			spec := ruleSpec{lines: make(map[string]int)}
+			if i < len(ruleLines) {
+				spec.line, spec.lines = ruleLines[i].line, ruleLines[i].lines
+			}
+			for _, name := range sortedKeys(rule) {
+				if err := spec.set(name, rule[name]); err != nil {
+					return nil, err
+				}
+			}
+			specs = append(specs, spec)
+		}
+	}
+	return specs, nil
+}
+
+// tomlTables returns the tables of an array of tables, written as [[rules]] tables or as an inline array
+func tomlTables(value interface{}) ([]map[string]interface{}, bool) {
+	switch value := value.(type) {
+	case []map[string]interface{}:
+		return value, true
+	case []interface{}:
+		tables := make([]map[string]interface{}, len(value))
+		for i, item := range value {
+			table, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			tables[i] = table
+		}
+		return tables, true
+	}
+	return nil, false
+}
+
+// tomlRuleLines finds the lines of the rules and their keys, since the TOML decoder does not keep them.
+// Rules are either [[rules]] tables or inline tables in a rules = [...] array.
+func tomlRuleLines(data []byte) []ruleSpec {
+	var rules []ruleSpec
+	inRule, inArray := false, false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "[") && !inArray {
+			inRule = strings.Join(strings.Fields(strings.Trim(text, "[]")), "") == "rules" &&
+				strings.HasPrefix(text, "[[")
+			if inRule {
+				rules = append(rules, ruleSpec{line: line, lines: make(map[string]int)})
+			}
+			continue
+		}
+		equals := strings.Index(text, "=")
+		if equals > 0 && !inRule && !inArray && strings.Trim(strings.TrimSpace(text[:equals]), `"'`) == "rules" {
+			inArray, text = true, text[equals+1:]
+		}
+		if inArray {
+			inArray = inlineRuleLines(&rules, text, line)
+			continue
+		}
+		if inRule && equals > 0 {
+			key := strings.Trim(strings.TrimSpace(text[:equals]), `"'`)
+			rules[len(rules)-1].lines[key] = line
+		}
+	}
+	return rules
+}
+
+// inlineRuleLines adds the inline tables that start on a line of a rules array and the keys on it to the rules.
+// It returns false when the array ends on the line.
+func inlineRuleLines(rules *[]ruleSpec, text string, line int) bool {
+	var quote byte
+	keyStart, depth := 0, 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return true
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth--; depth < 0 {
+				return false
+			}
+		case c == '{':
+			*rules = append(*rules, ruleSpec{line: line, lines: make(map[string]int)})
+			keyStart = i + 1
+		case c == ',':
+			keyStart = i + 1
+		case c == '=' && len(*rules) > 0:
+			key := strings.Trim(strings.TrimSpace(text[keyStart:i]), `"'`)
+			(*rules)[len(*rules)-1].lines[key] = line
+		}
+	}
+	return true
+}
+
+// set sets the field of the rule with given name to a value decoded from a rule file.
+// The field is recorded in the lines of the rule, at the line of the rule if its own line is not known.
+func (s *ruleSpec) set(name string, value interface{}) error {
+	if _, ok := s.lines[name]; !ok {
+		s.lines[name] = s.line
+	}
+	stringFields := map[string]*string{
+		"match":   &s.match,
+		"text":    &s.text,
+		"pattern": &s.pattern,
+		"open":    &s.open,
+		"close":   &s.close,
+		"layout":  &s.layout,
+		"style":   &s.style,
+	}
+	intFields := map[string]*int{"n": &s.n, "priority": &s.priority}
+
+	if field, ok := stringFields[name]; ok {
+		str, ok := value.(string)
+		if !ok {
+			return s.errorAt(name, fmt.Errorf("%s must be a string", name))
+		}
+		*field = str
+		return nil
+	}
+	if field, ok := intFields[name]; ok {
+		switch number := value.(type) {
+		case int:
+			*field = number
+		case int64:
+			*field = int(number)
+		default:
+			return s.errorAt(name, fmt.Errorf("%s must be an integer", name))
+		}
+		return nil
+	}
+	return s.errorAt(name, fmt.Errorf("unknown field %q", name))
+}
+
+// errorAt returns an error at the line of given field, or of the rule if the field is not known
+func (s ruleSpec) errorAt(field string, err error) error {
+	line, ok := s.lines[field]
+	if !ok {
+		line = s.line
+	}
+	return &RuleError{Line: line, Err: err}
+}
+
+func (s ruleSpec) markRule() (MarkRule, error) {
+	style, err := ParseStyle(s.style)
+	if err != nil {
+		return MarkRule{}, s.errorAt("style", err)
+	}
+	matcher, err := s.matcher()
+	if err != nil {
+		return MarkRule{}, err
+	}
+	return MarkRule{Matcher: matcher, Style: style, Priority: s.priority}, nil
+}
+
+func (s ruleSpec) matcher() (Matcher, error) {
+	required := func(fields ...string) error {
+		for _, field := range fields {
+			if _, ok := s.lines[field]; !ok {
+				return s.errorAt("match", fmt.Errorf("%s matcher needs %s", s.match, field))
+			}
+		}
+		return nil
+	}
+
+	switch s.match {
+	case "all":
+		if err := required("text"); err != nil {
+			return nil, err
+		}
+		return MatchAll(s.text), nil
+	case "n":
+		if err := required("text", "n"); err != nil {
+			return nil, err
+		}
+		if s.n <= 0 {
+			return nil, s.errorAt("n", errors.New("n must be positive"))
+		}
+		return MatchN(s.text, s.n), nil
+	case "regexp":
+		if err := required("pattern"); err != nil {
+			return nil, err
+		}
+		r, err := regexp.Compile(s.pattern)
+		if err != nil {
+			return nil, s.errorAt("pattern", err)
+		}
+		return MatchRegexp(r), nil
+	case "surrounded":
+		if err := required("open", "close"); err != nil {
+			return nil, err
+		}
+		if s.open == "" {
+			return nil, s.errorAt("open", errors.New("open must not be empty"))
+		}
+		if s.close == "" {
+			return nil, s.errorAt("close", errors.New("close must not be empty"))
+		}
+		return MatchSurrounded(s.open, s.close), nil
+	case "timestamp":
+		if err := required("layout"); err != nil {
+			return nil, err
+		}
+		layout, err := ParseTimestampLayout(s.layout)
+		if err != nil {
+			return nil, s.errorAt("layout", err)
+		}
+		return MatchTimestamp(layout), nil
+	case "email":
+		return MatchEmail(), nil
+	case "days":
+		return MatchDaysOfWeek(), nil
+	case "ip":
+		return MatchIP(), nil
+	case "ipv4":
+		return MatchIPv4(), nil
+	case "ipv6":
+		return MatchIPv6(), nil
+	case "cidr":
+		return MatchCIDR(), nil
+	case "hostport":
+		return MatchHostPort(), nil
+	case "url":
+		return MatchURL(), nil
+	case "uuid":
+		return MatchUUID(), nil
+	case "gitsha":
+		return MatchGitSHA(), nil
+	case "sha1":
+		return MatchSHA1(), nil
+	case "sha256":
+		return MatchSHA256(), nil
+	case "ulid":
+		return MatchULID(), nil
+	case "number":
+		return MatchNumber(CommonUnits...), nil
+	case "duration":
+		return MatchDuration(), nil
+	case "bytesize":
+		return MatchByteSize(), nil
+	case "":
+		return nil, s.errorAt("match", errors.New("rule needs a match type"))
+	}
+	return nil, s.errorAt("match", fmt.Errorf("unknown match type %q", s.match))
+}
+
+// ParseTimestampLayout returns the time layout with given name, such as RFC3339 or Kitchen,
+// or the layout itself if MatchTimestamp supports it
+func ParseTimestampLayout(layout string) (string, error) {
+	if named, ok := timestampLayoutNames[layout]; ok {
+		return named, nil
+	}
+	if _, ok := timestampLayoutRegexps[layout]; ok {
+		return layout, nil
+	}
+	return "", fmt.Errorf("unsupported timestamp layout %q", layout)
+}
+
+var parserErrorRegexp = regexp.MustCompile(`^(?:yaml: )?(?:Near )?line (\d+)(?: \(last key parsed '[^']*'\))?: `)
+
+// parserError moves the line number in an error of the YAML or TOML parser to a RuleError
+func parserError(err error) error {
+	match := parserErrorRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &RuleError{Err: err}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &RuleError{Line: line, Err: errors.New(strings.TrimPrefix(err.Error(), match[0]))}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
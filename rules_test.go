@@ -0,0 +1,80 @@
+package marker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadRulesFile(t *testing.T) {
+	str := "2019-10-18T10:00:00+03:00 [api] ERROR WARN WARN id=42 mail admin@example.com on Friday"
+
+	var outputs []string
+	for _, path := range []string{"testdata/rules.yaml", "testdata/rules.json", "testdata/rules.toml", "testdata/rules_tables.toml"} {
+		rules, err := LoadRulesFile(path)
+		assert.Nil(t, err, path)
+		assert.Len(t, rules, 7, path)
+		assert.Equal(t, Fg(Red).On(White).With(Bold), rules[0].Style, path)
+		assert.Equal(t, Fg(RGB(255, 135, 0)), rules[2].Style, path)
+		assert.Equal(t, 2, rules[2].Priority, path)
+		outputs = append(outputs, Render(str, ANSIRenderer{}, rules...))
+	}
+
+	marked := "\x1b[2m2019-10-18T10:00:00+03:00\x1b[0m \x1b[34m[api]\x1b[0m \x1b[1;31;47mERROR\x1b[0m \x1b[33mWARN\x1b[0m WARN " +
+		"\x1b[38;2;255;135;0mid=42\x1b[0m mail \x1b[4;36madmin@example.com\x1b[0m on \x1b[48;5;236mFriday\x1b[0m"
+	assert.Equal(t, []string{marked, marked, marked, marked}, outputs)
+
+	_, err := LoadRulesFile("testdata/rules.ini")
+	assert.EqualError(t, err, "testdata/rules.ini: unknown rule file format")
+}
+
+func Test_LoadRulesMatchTypes(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader("rules:\n  - match: hostport\n  - match: cidr\n  - match: ip\n  - match: url\n  - match: uuid\n  - match: gitsha\n  - match: number\n  - match: duration\n  - match: bytesize\n"), YAML)
+	assert.Nil(t, err)
+	if assert.Len(t, rules, 9) {
+		str := "dial [::1]:80 from 10.0.0.0/8 for http://example.com. at 3aee3da as 123e4567-e89b-12d3-a456-426614174000 " +
+			"in 12ms for 5MiB"
+		assert.Equal(t, []string{"[::1]:80"}, spanTexts(t, rules[0].Matcher, str))
+		assert.Equal(t, []string{"10.0.0.0/8"}, spanTexts(t, rules[1].Matcher, str))
+		assert.Equal(t, []string{"::1", "10.0.0.0"}, spanTexts(t, rules[2].Matcher, str))
+		assert.Equal(t, []string{"http://example.com"}, spanTexts(t, rules[3].Matcher, str))
+		assert.Equal(t, []string{"123e4567-e89b-12d3-a456-426614174000"}, spanTexts(t, rules[4].Matcher, str))
+		assert.Equal(t, []string{"3aee3da"}, spanTexts(t, rules[5].Matcher, str))
+		assert.Equal(t, []string{"12ms", "5MiB"}, spanTexts(t, rules[6].Matcher, str))
+		assert.Equal(t, []string{"12ms"}, spanTexts(t, rules[7].Matcher, str))
+		assert.Equal(t, []string{"5MiB"}, spanTexts(t, rules[8].Matcher, str))
+	}
+}
+
+func Test_LoadRulesErrors(t *testing.T) {
+	tests := []struct {
+		format   RuleFormat
+		rules    string
+		expected string
+	}{
+		{format: YAML, rules: "rules:\n  - match: regexp\n    pattern: '('\n", expected: "line 3: error parsing regexp: missing closing ): `(`"},
+		{format: YAML, rules: "rules:\n  - match: all\n    text: x\n    style: bold redd\n", expected: `line 4: invalid style "bold redd": unknown color "redd"`},
+		{format: YAML, rules: "rules:\n  - match: everything\n", expected: `line 2: unknown match type "everything"`},
+		{format: YAML, rules: "rules:\n  - match: all\n", expected: "line 2: all matcher needs text"},
+		{format: YAML, rules: "rules:\n  - match: n\n    text: x\n    n: two\n", expected: "line 4: n must be an integer"},
+		{format: YAML, rules: "rules:\n  - match: all\n    txt: x\n", expected: `line 3: unknown field "txt"`},
+		{format: YAML, rules: "rule:\n  - match: all\n", expected: `line 1: unknown key "rule"`},
+		{format: YAML, rules: "rules:\n  - match: all\n  text: x\n", expected: "line 1: did not find expected '-' indicator"},
+		{format: JSON, rules: "{\"rules\": [\n\t{\"match\": \"timestamp\",\n\t \"layout\": \"RFC9999\"}\n]}", expected: `line 3: unsupported timestamp layout "RFC9999"`},
+		{format: YAML, rules: "rules:\n  - match: surrounded\n    open: ''\n    close: ']'\n", expected: "line 3: open must not be empty"},
+		{format: JSON, rules: "{\"rules\": [\n\t{\"match\": \"surrounded\",\n\t \"open\": \"[\",\n\t \"close\": \"\"}\n]}", expected: "line 4: close must not be empty"},
+		{format: JSON, rules: "{\"rules\": [\n\t{\"match\": \"all\",}\n]}", expected: "line 2: invalid character '}' looking for beginning of object key string"},
+		{format: TOML, rules: "[[rules]]\nmatch = \"all\"\ntext = \"x\"\n\n[[rules]]\nmatch = \"regexp\"\npattern = \"[\"\n", expected: "line 7: error parsing regexp: missing closing ]: `[`"},
+		{format: TOML, rules: "[[rules]]\nmatch = \"all\"\ntext = x\n", expected: "line 3: expected value but found \"x\" instead"},
+		{format: TOML, rules: "[[rules]]\nmatch = \"n\"\ntext = \"x\"\n", expected: "line 2: n matcher needs n"},
+		{format: TOML, rules: "rules = [\n  {match = \"all\", text = \"{x}\"},\n\n  {match = \"all\", text = \"y\", style = \"redd\"},\n]\n", expected: `line 4: invalid style "redd": unknown color "redd"`},
+		{format: TOML, rules: "rules = [{match = \"n\", text = \"x\", n = 0}]\n", expected: "line 1: n must be positive"},
+		{format: TOML, rules: "rules = [\"all\"]\n", expected: "rules must be an array of tables"},
+	}
+
+	for _, testCase := range tests {
+		_, err := LoadRules(strings.NewReader(testCase.rules), testCase.format)
+		assert.EqualError(t, err, testCase.expected, testCase.rules)
+	}
+}
@@ -0,0 +1,458 @@
+package marker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Style describes how marked text looks
+type Style interface {
+	Foreground() Color
+	Background() Color
+	Attributes() Attribute
+}
+
+// UnderlineStyle is a Style that also sets the color of the underline. Terminals that do not support underline colors
+// draw the underline with the foreground color.
+type UnderlineStyle interface {
+	Style
+	UnderlineColor() Color
+}
+
+// StyleFunc chooses the style of a span found by a Matcher, by its text or position.
+// Returning nil leaves the span with the style it would have without StyleFunc.
+type StyleFunc func(span Span) Style
+
+// StyleByText creates a StyleFunc that chooses the style keyed by the text of the span
+func StyleByText(styles map[string]Style) StyleFunc {
+	return func(span Span) Style {
+		return styles[span.Text]
+	}
+}
+
+// Attribute is a set of text attributes of a Style
+type Attribute uint16
+
+// Text attributes, they can be combined with |
+const (
+	Bold Attribute = 1 << iota
+	Faint
+	Italic
+	Underline
+	BlinkSlow
+	BlinkRapid
+	ReverseVideo
+	Concealed
+	CrossedOut
+)
+
+// attributeCodes are the SGR parameters of attributes in the order of their bits
+var attributeCodes = [...]int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+var attributeNames = [...]string{"bold", "faint", "italic", "underline", "blink-slow", "blink-rapid",
+	"reverse-video", "concealed", "crossed-out"}
+
+// Names returns the names of the attributes in the set such as "bold" or "underline"
+func (a Attribute) Names() []string {
+	var names []string
+	for i, name := range attributeNames {
+		if a&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota
+	colorBasic
+	colorIndexed
+	colorRGB
+)
+
+// Color is a foreground, background or underline color of a Style. The zero value is the default color of the terminal.
+type Color struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+// Basic colors of terminals
+var (
+	Black         = Color{kind: colorBasic, index: 0}
+	Red           = Color{kind: colorBasic, index: 1}
+	Green         = Color{kind: colorBasic, index: 2}
+	Yellow        = Color{kind: colorBasic, index: 3}
+	Blue          = Color{kind: colorBasic, index: 4}
+	Magenta       = Color{kind: colorBasic, index: 5}
+	Cyan          = Color{kind: colorBasic, index: 6}
+	White         = Color{kind: colorBasic, index: 7}
+	BrightBlack   = Color{kind: colorBasic, index: 8}
+	BrightRed     = Color{kind: colorBasic, index: 9}
+	BrightGreen   = Color{kind: colorBasic, index: 10}
+	BrightYellow  = Color{kind: colorBasic, index: 11}
+	BrightBlue    = Color{kind: colorBasic, index: 12}
+	BrightMagenta = Color{kind: colorBasic, index: 13}
+	BrightCyan    = Color{kind: colorBasic, index: 14}
+	BrightWhite   = Color{kind: colorBasic, index: 15}
+)
+
+// Color256 creates a color of the 256-color palette of terminals, where the first 16 are the basic colors,
+// the next 216 are a 6x6x6 color cube and the last 24 are shades of gray
+func Color256(index uint8) Color {
+	return Color{kind: colorIndexed, index: index}
+}
+
+// RGB creates a 24-bit truecolor
+func RGB(r, g, b uint8) Color {
+	return Color{kind: colorRGB, r: r, g: g, b: b}
+}
+
+// HexColor creates a 24-bit truecolor from #RRGGBB or #RGB notation, where # is optional
+func HexColor(hex string) (Color, error) {
+	digits := strings.TrimPrefix(hex, "#")
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if len(digits) != 6 || err != nil {
+		return Color{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	return RGB(uint8(value>>16), uint8(value>>8), uint8(value)), nil
+}
+
+// MustHexColor is like HexColor but panics if the color is invalid
+func MustHexColor(hex string) Color {
+	c, err := HexColor(hex)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// IsDefault reports whether the color is the default color of the terminal
+func (c Color) IsDefault() bool {
+	return c.kind == colorDefault
+}
+
+var basicColorNames = [16]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow", "bright-blue", "bright-magenta", "bright-cyan", "bright-white"}
+
+// String returns the name of the color such as "red" or "bright-blue", the index of a 256-color palette color
+// such as "208", #rrggbb for a truecolor, or an empty string for the default color
+func (c Color) String() string {
+	switch c.kind {
+	case colorBasic:
+		return basicColorNames[c.index]
+	case colorIndexed:
+		return strconv.Itoa(int(c.index))
+	case colorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	}
+	return ""
+}
+
+// Hex returns the color as #RRGGBB using given palette for the basic colors,
+// or an empty string for the default color
+func (c Color) Hex(palette Palette) string {
+	switch {
+	case c.kind == colorDefault:
+		return ""
+	case c.kind == colorRGB:
+		return c.String()
+	case c.index < 16:
+		return palette[c.index]
+	}
+	r, g, b := c.RGB()
+	return RGB(r, g, b).String()
+}
+
+// RGB returns the red, green and blue components of the color, using DefaultPalette for the basic colors.
+// The default color is black.
+func (c Color) RGB() (uint8, uint8, uint8) {
+	switch {
+	case c.kind == colorDefault:
+		return 0, 0, 0
+	case c.kind == colorRGB:
+		return c.r, c.g, c.b
+	case c.index < 16:
+		value, _ := strconv.ParseUint(DefaultPalette[c.index][1:], 16, 32)
+		return uint8(value >> 16), uint8(value >> 8), uint8(value)
+	case c.index < 232:
+		cube := c.index - 16
+		return cubeLevels[cube/36], cubeLevels[cube/6%6], cubeLevels[cube%6]
+	}
+	gray := 8 + (c.index-232)*10
+	return gray, gray, gray
+}
+
+// cubeLevels are the intensities of the 6x6x6 color cube of the 256-color palette
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Color targets of SGR parameters
+const (
+	foregroundTarget = 38
+	backgroundTarget = 48
+	underlineTarget  = 58
+)
+
+// sgr returns the SGR parameters setting the color for given target
+func (c Color) sgr(target int) []string {
+	switch {
+	case c.kind == colorDefault:
+		return nil
+	case c.kind == colorRGB:
+		return []string{strconv.Itoa(target), "2", strconv.Itoa(int(c.r)), strconv.Itoa(int(c.g)), strconv.Itoa(int(c.b))}
+	case c.kind == colorIndexed || target == underlineTarget:
+		return []string{strconv.Itoa(target), "5", strconv.Itoa(int(c.index))}
+	}
+	base := target - 8
+	if c.index >= 8 {
+		base = target + 52 - 8
+	}
+	return []string{strconv.Itoa(base + int(c.index))}
+}
+
+// Palette is the list of #RRGGBB values that the basic colors are shown with
+type Palette [16]string
+
+// DefaultPalette is the palette of xterm
+var DefaultPalette = Palette{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// TextStyle is a Style made of a foreground color, a background color, an underline color and attributes
+type TextStyle struct {
+	Fg    Color
+	Bg    Color
+	Ul    Color
+	Attrs Attribute
+}
+
+// Fg creates a TextStyle with given foreground color
+func Fg(c Color) TextStyle {
+	return TextStyle{Fg: c}
+}
+
+// Bg creates a TextStyle with given background color
+func Bg(c Color) TextStyle {
+	return TextStyle{Bg: c}
+}
+
+// On returns a copy of the style with given background color
+func (s TextStyle) On(c Color) TextStyle {
+	s.Bg = c
+	return s
+}
+
+// With returns a copy of the style with given attributes added
+func (s TextStyle) With(attrs Attribute) TextStyle {
+	s.Attrs |= attrs
+	return s
+}
+
+// UnderlinedIn returns a copy of the style underlined with given color
+func (s TextStyle) UnderlinedIn(c Color) TextStyle {
+	s.Ul = c
+	s.Attrs |= Underline
+	return s
+}
+
+// Foreground returns the foreground color of the style
+func (s TextStyle) Foreground() Color {
+	return s.Fg
+}
+
+// Background returns the background color of the style
+func (s TextStyle) Background() Color {
+	return s.Bg
+}
+
+// Attributes returns the text attributes of the style
+func (s TextStyle) Attributes() Attribute {
+	return s.Attrs
+}
+
+// UnderlineColor returns the underline color of the style
+func (s TextStyle) UnderlineColor() Color {
+	return s.Ul
+}
+
+// FromColor adapts a color of github.com/fatih/color to a Style.
+// Neither DisableColor of the color nor the global color.NoColor switch is followed,
+// as color modes of WriteMarker and renderers decide whether to show colors.
+func FromColor(c *color.Color) TextStyle {
+	if c == nil {
+		return TextStyle{}
+	}
+	enabled := *c
+	enabled.EnableColor()
+	parameters, _ := sgrParameters(strings.TrimSuffix(enabled.Sprint(), resetSequence))
+	return styleFromSGR(TextStyle{}, parameters)
+}
+
+// StyleSequence returns the escape sequence that turns given style on, or an empty string for an empty style
+func StyleSequence(s Style) string {
+	if s == nil {
+		return ""
+	}
+	var parameters []string
+	for i, code := range attributeCodes {
+		if s.Attributes()&(1<<uint(i)) != 0 {
+			parameters = append(parameters, strconv.Itoa(code))
+		}
+	}
+	parameters = append(parameters, s.Foreground().sgr(foregroundTarget)...)
+	parameters = append(parameters, s.Background().sgr(backgroundTarget)...)
+	parameters = append(parameters, underlineColor(s).sgr(underlineTarget)...)
+	if len(parameters) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(parameters, ";") + "m"
+}
+
+// ResetSequence returns the escape sequence that turns all styles off
+func ResetSequence() string {
+	return resetSequence
+}
+
+// styleFromSGR applies the parameters of an SGR sequence on given style
+func styleFromSGR(s TextStyle, parameters []string) TextStyle {
+	for i := 0; i < len(parameters); i++ {
+		code, err := strconv.Atoi(parameters[i])
+		if err != nil && parameters[i] != "" {
+			continue
+		}
+		switch {
+		case code == foregroundTarget || code == backgroundTarget || code == underlineTarget:
+			c, n := extendedColor(parameters[i+1:])
+			i += n
+			switch {
+			case n == 0:
+			case code == foregroundTarget:
+				s.Fg = c
+			case code == backgroundTarget:
+				s.Bg = c
+			default:
+				s.Ul = c
+			}
+		case code == 0:
+			s = TextStyle{}
+		case code >= 1 && code <= 9:
+			s.Attrs |= 1 << uint(code-1)
+		case code == 22:
+			s.Attrs &^= Bold | Faint
+		case code >= 23 && code <= 29 && code != 26:
+			s.Attrs &^= 1 << uint(code-21)
+		case code >= 30 && code <= 37:
+			s.Fg = Color{kind: colorBasic, index: uint8(code - 30)}
+		case code == 39:
+			s.Fg = Color{}
+		case code >= 40 && code <= 47:
+			s.Bg = Color{kind: colorBasic, index: uint8(code - 40)}
+		case code == 49:
+			s.Bg = Color{}
+		case code == 59:
+			s.Ul = Color{}
+		case code >= 90 && code <= 97:
+			s.Fg = Color{kind: colorBasic, index: uint8(code - 90 + 8)}
+		case code >= 100 && code <= 107:
+			s.Bg = Color{kind: colorBasic, index: uint8(code - 100 + 8)}
+		}
+	}
+	return s
+}
+
+// extendedColor reads an extended color, such as 5;n or 2;r;g;b, and returns it with the number of parameters it takes
+func extendedColor(parameters []string) (Color, int) {
+	if len(parameters) >= 2 && parameters[0] == "5" {
+		return Color256(sgrComponent(parameters[1])), 2
+	}
+	if len(parameters) >= 4 && parameters[0] == "2" {
+		return RGB(sgrComponent(parameters[1]), sgrComponent(parameters[2]), sgrComponent(parameters[3])), 4
+	}
+	return Color{}, 0
+}
+
+func sgrComponent(parameter string) uint8 {
+	value, _ := strconv.ParseUint(parameter, 10, 8)
+	return uint8(value)
+}
+
+// underlineColor returns the underline color of given style if it sets one
+func underlineColor(s Style) Color {
+	if underlined, ok := s.(UnderlineStyle); ok {
+		return underlined.UnderlineColor()
+	}
+	return Color{}
+}
+
+// ParseColor parses a color written as the name of a basic color such as "red" or "bright-blue", an index of the
+// 256-color palette such as "208", or a truecolor such as "#ff8700"
+func ParseColor(spec string) (Color, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	for i, name := range basicColorNames {
+		if spec == name {
+			return Color{kind: colorBasic, index: uint8(i)}, nil
+		}
+	}
+	if strings.HasPrefix(spec, "#") {
+		return HexColor(spec)
+	}
+	if index, err := strconv.ParseUint(spec, 10, 8); err == nil {
+		return Color256(uint8(index)), nil
+	}
+	return Color{}, fmt.Errorf("unknown color %q", spec)
+}
+
+// ParseStyle parses a style written as attributes and colors such as "bold red on white".
+// The color before "on" is the foreground color and the color right after it is the background color,
+// so a style has at most one of each. Attributes are named as Attribute.Names returns them.
+func ParseStyle(spec string) (TextStyle, error) {
+	var style TextStyle
+	hasFg, hasBg, background := false, false, false
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if word == "on" {
+			if hasBg || background {
+				return TextStyle{}, fmt.Errorf("invalid style %q: more than one background color", spec)
+			}
+			background = true
+			continue
+		}
+		if attr, ok := attributeByName(word); ok && !background {
+			style.Attrs |= attr
+			continue
+		}
+		c, err := ParseColor(word)
+		if err != nil {
+			return TextStyle{}, fmt.Errorf("invalid style %q: %v", spec, err)
+		}
+		switch {
+		case background:
+			style.Bg, hasBg, background = c, true, false
+		case hasFg:
+			return TextStyle{}, fmt.Errorf("invalid style %q: more than one foreground color", spec)
+		default:
+			style.Fg, hasFg = c, true
+		}
+	}
+	if background {
+		return TextStyle{}, fmt.Errorf(`invalid style %q: "on" without a background color`, spec)
+	}
+	return style, nil
+}
+
+func attributeByName(name string) (Attribute, bool) {
+	for i, attributeName := range attributeNames {
+		if name == attributeName {
+			return 1 << uint(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,203 @@
+package marker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StyleByText(t *testing.T) {
+	redFg := Fg(Red)
+	yellowFg := Fg(Yellow)
+
+	styleFunc := StyleByText(map[string]Style{"ERROR": redFg, "WARN": yellowFg})
+
+	assert.Equal(t, redFg, styleFunc(Span{Start: 0, End: 5, Text: "ERROR"}))
+	assert.Equal(t, yellowFg, styleFunc(Span{Start: 0, End: 4, Text: "WARN"}))
+	assert.Nil(t, styleFunc(Span{Start: 0, End: 4, Text: "INFO"}))
+}
+
+func Test_MarkFunc(t *testing.T) {
+	redFg := color.New(color.FgRed)
+	redFg.EnableColor()
+	red := redFg.SprintFunc()
+	yellowFg := color.New(color.FgYellow)
+	yellowFg.EnableColor()
+	yellow := yellowFg.SprintFunc()
+	greenFg := color.New(color.FgGreen)
+	greenFg.EnableColor()
+	green := greenFg.SprintFunc()
+
+	levels := MatchMultiple([]string{"INFO", "WARN", "ERROR"})
+	styleFunc := StyleByText(map[string]Style{"INFO": FromColor(greenFg), "WARN": FromColor(yellowFg), "ERROR": FromColor(redFg)})
+
+	marked := MarkFunc("INFO started, WARN slow, ERROR failed", levels, styleFunc)
+	expected := fmt.Sprintf("%s started, %s slow, %s failed", green("INFO"), yellow("WARN"), red("ERROR"))
+	assert.Equal(t, expected, marked)
+
+	latency := func(span Span) Style {
+		ms, _ := strconv.Atoi(span.Text[:len(span.Text)-2])
+		if ms > 500 {
+			return FromColor(redFg)
+		}
+		return FromColor(greenFg)
+	}
+
+	r := regexp.MustCompile(`\d+ms`)
+	b := &MarkBuilder{}
+	marked = b.SetString("GET / 812ms, GET /health 3ms").
+		MarkFunc(MatchRegexp(r), latency).
+		Build()
+	expected = fmt.Sprintf("GET / %s, GET /health %s", red("812ms"), green("3ms"))
+	assert.Equal(t, expected, marked)
+
+	firstOnly := func(span Span) Style {
+		if span.Start == 0 {
+			return FromColor(redFg)
+		}
+		return nil
+	}
+	marked = markRules("ERROR ERROR", []MarkRule{{Matcher: MatchAll("ERROR"), Style: FromColor(greenFg), StyleFunc: firstOnly}}, OverlapSplit)
+	expected = fmt.Sprintf("%s %s", red("ERROR"), green("ERROR"))
+	assert.Equal(t, expected, marked)
+}
+
+func Test_StyleSequence(t *testing.T) {
+	tests := []struct {
+		style    Style
+		expected string
+	}{
+		{style: nil, expected: ""},
+		{style: TextStyle{}, expected: ""},
+		{style: Fg(Red), expected: "\x1b[31m"},
+		{style: Fg(BrightMagenta).On(White), expected: "\x1b[95;47m"},
+		{style: Bg(BrightBlue).With(Bold | Underline), expected: "\x1b[1;4;104m"},
+		{style: Fg(Green).With(CrossedOut), expected: "\x1b[9;32m"},
+		{style: Fg(Color256(208)).On(RGB(0, 95, 255)), expected: "\x1b[38;5;208;48;2;0;95;255m"},
+		{style: Fg(MustHexColor("#ff8700")).UnderlinedIn(Red), expected: "\x1b[4;38;2;255;135;0;58;5;1m"},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, StyleSequence(testCase.style))
+	}
+	assert.Equal(t, "\x1b[0m", ResetSequence())
+}
+
+func Test_FromColor(t *testing.T) {
+	hiMagentaOnWhite := color.New(color.FgHiMagenta, color.BgWhite, color.Bold)
+	hiMagentaOnWhite.EnableColor()
+	assert.Equal(t, Fg(BrightMagenta).On(White).With(Bold), FromColor(hiMagentaOnWhite))
+
+	blackOnHiBlue := color.New(color.FgBlack, color.BgHiBlue, color.Underline, color.ReverseVideo)
+	blackOnHiBlue.EnableColor()
+	assert.Equal(t, Fg(Black).On(BrightBlue).With(Underline|ReverseVideo), FromColor(blackOnHiBlue))
+
+	disabled := color.New(color.FgRed)
+	disabled.DisableColor()
+	assert.Equal(t, Fg(Red), FromColor(disabled))
+	assert.Equal(t, TextStyle{}, FromColor(color.New()))
+
+	assert.Equal(t, TextStyle{}, FromColor(nil))
+
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+	color.NoColor = true
+	assert.Equal(t, Fg(Red).With(Bold), FromColor(color.New(color.FgRed, color.Bold)))
+}
+
+func Test_styleFromSGR(t *testing.T) {
+	style := styleFromSGR(TextStyle{}, []string{"1", "3", "31", "44"})
+	assert.Equal(t, Fg(Red).On(Blue).With(Bold|Italic), style)
+
+	style = styleFromSGR(style, []string{"22", "39", "38", "5", "4"})
+	assert.Equal(t, Fg(Color256(4)).On(Blue).With(Italic), style)
+
+	style = styleFromSGR(style, []string{"0", "97"})
+	assert.Equal(t, Fg(BrightWhite), style)
+
+	style = styleFromSGR(style, []string{"48", "2", "255", "135", "0", "4", "58", "5", "196"})
+	assert.Equal(t, Fg(BrightWhite).On(RGB(255, 135, 0)).UnderlinedIn(Color256(196)), style)
+
+	style = styleFromSGR(style, []string{"59", "38", "7", "24"})
+	assert.Equal(t, Fg(BrightWhite).On(RGB(255, 135, 0)).With(ReverseVideo), style)
+}
+
+func Test_HexColor(t *testing.T) {
+	c, err := HexColor("#ff8700")
+	assert.Nil(t, err)
+	assert.Equal(t, RGB(255, 135, 0), c)
+
+	c, err = HexColor("0af")
+	assert.Nil(t, err)
+	assert.Equal(t, RGB(0, 170, 255), c)
+
+	for _, invalid := range []string{"", "#ff87", "#gg8700", "#ff870000"} {
+		_, err = HexColor(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+	assert.Panics(t, func() { MustHexColor("red") })
+}
+
+func Test_ColorRGB(t *testing.T) {
+	tests := []struct {
+		color    Color
+		expected string
+		hex      string
+	}{
+		{color: Color{}, expected: "", hex: ""},
+		{color: Red, expected: "red", hex: "#cd0000"},
+		{color: Color256(9), expected: "9", hex: "#ff0000"},
+		{color: Color256(208), expected: "208", hex: "#ff8700"},
+		{color: Color256(244), expected: "244", hex: "#808080"},
+		{color: RGB(1, 2, 255), expected: "#0102ff", hex: "#0102ff"},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, testCase.color.String())
+		assert.Equal(t, testCase.hex, testCase.color.Hex(DefaultPalette))
+	}
+}
+
+func Test_ParseStyle(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected TextStyle
+	}{
+		{spec: "", expected: TextStyle{}},
+		{spec: "red", expected: Fg(Red)},
+		{spec: "bold red on white", expected: Fg(Red).On(White).With(Bold)},
+		{spec: "Italic Underline bright-blue", expected: Fg(BrightBlue).With(Italic | Underline)},
+		{spec: "on 236", expected: Bg(Color256(236))},
+		{spec: "#ff8700 on #000", expected: Fg(RGB(255, 135, 0)).On(RGB(0, 0, 0))},
+		{spec: "on white bold red", expected: Fg(Red).On(White).With(Bold)},
+	}
+	for _, testCase := range tests {
+		style, err := ParseStyle(testCase.spec)
+		assert.Nil(t, err, testCase.spec)
+		assert.Equal(t, testCase.expected, style, testCase.spec)
+	}
+
+	for _, invalid := range []string{"redd", "bold on 256", "#ff87"} {
+		_, err := ParseStyle(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+
+	invalidStyles := []struct {
+		spec     string
+		expected string
+	}{
+		{spec: "red blue", expected: `invalid style "red blue": more than one foreground color`},
+		{spec: "red on white on blue", expected: `invalid style "red on white on blue": more than one background color`},
+		{spec: "red on on white", expected: `invalid style "red on on white": more than one background color`},
+		{spec: "bold red on", expected: `invalid style "bold red on": "on" without a background color`},
+		{spec: "red on bold", expected: `invalid style "red on bold": unknown color "bold"`},
+	}
+	for _, testCase := range invalidStyles {
+		_, err := ParseStyle(testCase.spec)
+		assert.EqualError(t, err, testCase.expected)
+	}
+}
@@ -0,0 +1,208 @@
+package marker
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Theme is the look of the terminal that SVGRenderer draws
+type Theme struct {
+	Foreground string
+	Background string
+	Palette    Palette
+	FontFamily string
+	FontSize   int
+	LineHeight int
+	PaddingX   int
+	PaddingY   int
+	Radius     int
+}
+
+// DefaultTheme is the theme of the images in the documentation of marker
+var DefaultTheme = Theme{
+	Foreground: "#e9e9f4",
+	Background: "#282936",
+	Palette: Palette{
+		"#282936", "#ea51b2", "#00f769", "#ebff87", "#62d6e8", "#b45bcf", "#a1efe4", "#e9e9f4",
+		"#626483", "#b45bcf", "#3a3c4e", "#4d4f68", "#62d6e8", "#f1f2f8", "#00f769", "#f7f7fb",
+	},
+	FontFamily: `SFMono-Regular, Monaco, Menlo, Consolas, 'Liberation Mono', Courier, monospace`,
+	FontSize:   20,
+	LineHeight: 24,
+	PaddingX:   18,
+	PaddingY:   14,
+	Radius:     5,
+}
+
+// cellWidthRatio is the width of a monospace cell relative to the font size
+const cellWidthRatio = 0.6
+
+const tabWidth = 8
+
+// SVGRenderer renders marked text as a self-contained SVG image of a terminal showing the text.
+// Styles set by the escape sequences of the original text are rendered as well, so rendering a string without any
+// rules draws raw ANSI output. Theme is used for colors and fonts, or DefaultTheme when it is not set.
+// The image is at least Columns cells wide.
+type SVGRenderer struct {
+	Theme   *Theme
+	Columns int
+}
+
+// svgCell is a run of text starting at a column of a line
+type svgCell struct {
+	column int
+	width  int
+	text   string
+	style  TextStyle
+}
+
+// Render returns the marked text as an SVG document
+func (r SVGRenderer) Render(marked Marked) string {
+	theme := DefaultTheme
+	if r.Theme != nil {
+		theme = *r.Theme
+	}
+
+	lines := svgLines(marked.runs())
+	columns := r.Columns
+	for _, line := range lines {
+		if n := len(line); n > 0 {
+			columns = max(columns, line[n-1].column+line[n-1].width)
+		}
+	}
+
+	cellWidth := float64(theme.FontSize) * cellWidthRatio
+	width := float64(2*theme.PaddingX) + float64(columns)*cellWidth
+	height := 2*theme.PaddingY + len(lines)*theme.LineHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%d" viewBox="0 0 %[1]s %[2]d">`,
+		formatFloat(width), height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" rx="%d" fill="%s"/>`, theme.Radius, theme.Background)
+	fmt.Fprintf(&b, `<g font-family="%s" font-size="%d" fill="%s" xml:space="preserve">`,
+		html.EscapeString(theme.FontFamily), theme.FontSize, theme.Foreground)
+
+	baseline := float64(theme.LineHeight-theme.FontSize)/2 + float64(theme.FontSize)*0.8
+	for i, line := range lines {
+		top := theme.PaddingY + i*theme.LineHeight
+		for _, cell := range line {
+			if _, bg := theme.colors(cell.style); bg != "" {
+				fmt.Fprintf(&b, `<rect x="%s" y="%d" width="%s" height="%d" fill="%s"/>`,
+					formatFloat(float64(theme.PaddingX)+float64(cell.column)*cellWidth), top,
+					formatFloat(float64(cell.width)*cellWidth), theme.LineHeight, bg)
+			}
+		}
+
+		var text strings.Builder
+		for _, cell := range line {
+			if strings.TrimSpace(cell.text) == "" || cell.style.Attrs&Concealed != 0 {
+				continue
+			}
+			fmt.Fprintf(&text, `<tspan x="%s"%s>%s</tspan>`,
+				formatFloat(float64(theme.PaddingX)+float64(cell.column)*cellWidth),
+				theme.attributes(cell.style), html.EscapeString(cell.text))
+		}
+		if text.Len() > 0 {
+			fmt.Fprintf(&b, `<text y="%s">%s</text>`, formatFloat(float64(top)+baseline), text.String())
+		}
+	}
+
+	b.WriteString("</g></svg>\n")
+	return b.String()
+}
+
+// colors returns the fill colors of the text and the background of given style, where an empty background means
+// the background of the theme
+func (t Theme) colors(style TextStyle) (string, string) {
+	fg, bg := style.Fg.Hex(t.Palette), style.Bg.Hex(t.Palette)
+	if style.Attrs&ReverseVideo != 0 {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = t.Background
+		}
+		if bg == "" {
+			bg = t.Foreground
+		}
+	}
+	return fg, bg
+}
+
+func (t Theme) attributes(style TextStyle) string {
+	var b strings.Builder
+	if fg, _ := t.colors(style); fg != "" {
+		b.WriteString(` fill="` + fg + `"`)
+	}
+	if style.Attrs&Bold != 0 {
+		b.WriteString(` font-weight="bold"`)
+	}
+	if style.Attrs&Faint != 0 {
+		b.WriteString(` opacity="0.5"`)
+	}
+	if style.Attrs&Italic != 0 {
+		b.WriteString(` font-style="italic"`)
+	}
+	var decorations []string
+	if style.Attrs&Underline != 0 {
+		decorations = append(decorations, "underline")
+	}
+	if style.Attrs&CrossedOut != 0 {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		b.WriteString(` text-decoration="` + strings.Join(decorations, " ") + `"`)
+	}
+	return b.String()
+}
+
+// svgLines splits runs into lines of cells placed on the columns of a terminal.
+// Tabs are expanded to the next tab stop and other control characters are dropped.
+func svgLines(runs []run) [][]svgCell {
+	lines := [][]svgCell{nil}
+	column := 0
+	for _, run := range runs {
+		var text strings.Builder
+		start := column
+		flush := func() {
+			if column > start {
+				line := &lines[len(lines)-1]
+				*line = append(*line, svgCell{column: start, width: column - start, text: text.String(), style: run.style})
+			}
+			text.Reset()
+			start = column
+		}
+		for _, r := range run.text {
+			switch {
+			case r == '\n':
+				flush()
+				lines = append(lines, nil)
+				column, start = 0, 0
+			case r == '\t':
+				spaces := cellWidth(r, column)
+				text.WriteString(strings.Repeat(" ", spaces))
+				column += spaces
+			case r < ' ' || r == 0x7f:
+			default:
+				text.WriteRune(r)
+				column += cellWidth(r, column)
+			}
+		}
+		flush()
+	}
+	if len(lines) > 1 && lines[len(lines)-1] == nil {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
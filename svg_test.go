@@ -0,0 +1,46 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SVGRenderer(t *testing.T) {
+	theme := Theme{Foreground: "#ffffff", Background: "#000000", Palette: DefaultPalette, FontFamily: "monospace",
+		FontSize: 10, LineHeight: 10, PaddingX: 2, PaddingY: 2}
+
+	actual := Render("[ERROR]\t<sda> full\n", SVGRenderer{Theme: &theme}, renderTestRules...)
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="112" height="14" viewBox="0 0 112 14">` +
+		`<rect width="100%" height="100%" rx="0" fill="#000000"/>` +
+		`<g font-family="monospace" font-size="10" fill="#ffffff" xml:space="preserve">` +
+		`<text y="10"><tspan x="2" fill="#0000ee">[</tspan><tspan x="8" fill="#cd0000" font-weight="bold">ERROR</tspan>` +
+		`<tspan x="38" fill="#0000ee">]</tspan><tspan x="44"> &lt;sda&gt; full</tspan></text>` +
+		"</g></svg>\n"
+	assert.Equal(t, expected, actual)
+
+	raw := Render("ok\n\x1b[7mno\x1b[0m", SVGRenderer{Theme: &theme, Columns: 4})
+	expected = `<svg xmlns="http://www.w3.org/2000/svg" width="28" height="24" viewBox="0 0 28 24">` +
+		`<rect width="100%" height="100%" rx="0" fill="#000000"/>` +
+		`<g font-family="monospace" font-size="10" fill="#ffffff" xml:space="preserve">` +
+		`<text y="10"><tspan x="2">ok</tspan></text>` +
+		`<rect x="2" y="12" width="12" height="10" fill="#ffffff"/>` +
+		`<text y="20"><tspan x="2" fill="#000000">no</tspan></text>` +
+		"</g></svg>\n"
+	assert.Equal(t, expected, raw)
+}
+
+func Test_svgLines(t *testing.T) {
+	runs := []run{
+		{text: "a\tb\r\n", style: TextStyle{}},
+		{text: "\tc", style: Fg(Red)},
+		{text: "\n日本\tx", style: TextStyle{}},
+		{text: "é", style: Fg(Blue)},
+	}
+	expected := [][]svgCell{
+		{{column: 0, width: 9, text: "a       b"}},
+		{{column: 0, width: 9, text: "        c", style: Fg(Red)}},
+		{{column: 0, width: 9, text: "日本    x"}, {column: 9, width: 1, text: "é", style: Fg(Blue)}},
+	}
+	assert.Equal(t, expected, svgLines(runs))
+}
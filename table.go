@@ -0,0 +1,207 @@
+package marker
+
+import (
+	"io"
+	"strings"
+)
+
+// Border is the set of strings that a Table draws its lines with.
+// The zero value draws no lines and separates the columns with two spaces.
+type Border struct {
+	Horizontal string
+	Vertical   string
+
+	TopLeft, TopMiddle, TopRight          string
+	MiddleLeft, Middle, MiddleRight       string
+	BottomLeft, BottomMiddle, BottomRight string
+}
+
+// Borders of tables
+var (
+	BorderNone  = Border{}
+	BorderASCII = Border{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopMiddle: "+", TopRight: "+",
+		MiddleLeft: "+", Middle: "+", MiddleRight: "+",
+		BottomLeft: "+", BottomMiddle: "+", BottomRight: "+",
+	}
+	BorderLight = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopMiddle: "┬", TopRight: "┐",
+		MiddleLeft: "├", Middle: "┼", MiddleRight: "┤",
+		BottomLeft: "└", BottomMiddle: "┴", BottomRight: "┘",
+	}
+	BorderRounded = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "╭", TopMiddle: "┬", TopRight: "╮",
+		MiddleLeft: "├", Middle: "┼", MiddleRight: "┤",
+		BottomLeft: "╰", BottomMiddle: "┴", BottomRight: "╯",
+	}
+)
+
+// Table writes rows as aligned columns with their cells marked by rules.
+// Rows are kept until Flush, which computes the widths of the columns from the visible widths of the cells.
+type Table struct {
+	marker      *WriteMarker
+	header      []string
+	headerStyle Style
+	rows        [][]string
+	columnRules map[int][]MarkRule
+	alignments  map[int]Alignment
+	border      Border
+}
+
+// NewTable creates a Table that writes out to the given io.Writer.
+// Options choose the renderer, the overlap policy and the colors as they do for NewWriteMarker.
+// Tables are laid out for terminals, so the renderer is expected to be ANSIRenderer or PlainRenderer.
+func NewTable(writer io.Writer, options ...WriteMarkerOption) *Table {
+	return &Table{
+		marker:      NewWriteMarker(writer, options...),
+		columnRules: map[int][]MarkRule{},
+		alignments:  map[int]Alignment{},
+	}
+}
+
+// SetHeader sets the titles of the columns, which are written above the rows and not marked by rules
+func (t *Table) SetHeader(titles ...string) *Table {
+	t.header = titles
+	return t
+}
+
+// SetHeaderStyle sets the style of the header titles
+func (t *Table) SetHeaderStyle(style Style) *Table {
+	t.headerStyle = style
+	return t
+}
+
+// SetBorder sets the border that the table is drawn with, BorderNone by default
+func (t *Table) SetBorder(border Border) *Table {
+	t.border = border
+	return t
+}
+
+// SetAlignment sets the alignment of the column at given index, AlignLeft by default
+func (t *Table) SetAlignment(column int, align Alignment) *Table {
+	t.alignments[column] = align
+	return t
+}
+
+// AddRule appends a rule that marks the cells of all columns
+func (t *Table) AddRule(rule MarkRule) *Table {
+	t.marker.AddRule(rule)
+	return t
+}
+
+// AddColumnRule appends a rule that marks the cells of the column at given index only.
+// Column rules are applied after the rules of all columns.
+func (t *Table) AddColumnRule(column int, rule MarkRule) *Table {
+	t.columnRules[column] = append(t.columnRules[column], rule)
+	return t
+}
+
+// Append adds a row with given cells to the table
+func (t *Table) Append(cells ...string) *Table {
+	t.rows = append(t.rows, cells)
+	return t
+}
+
+// Flush writes the header and the rows to out, then clears the rows
+func (t *Table) Flush() error {
+	widths := t.columnWidths()
+	if len(widths) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	t.writeLine(&b, widths, t.border.TopLeft, t.border.TopMiddle, t.border.TopRight)
+	if t.header != nil {
+		t.writeRow(&b, widths, t.header, func(column int, cell string) string {
+			if t.headerStyle == nil {
+				return t.marker.renderer.Render(markSegmentsOf(cell, nil, t.marker.overlapPolicy))
+			}
+			rule := MarkRule{Matcher: matchWhole, Style: t.headerStyle}
+			return t.marker.renderer.Render(markSegmentsOf(cell, []MarkRule{rule}, t.marker.overlapPolicy))
+		})
+		t.writeLine(&b, widths, t.border.MiddleLeft, t.border.Middle, t.border.MiddleRight)
+	}
+	for _, row := range t.rows {
+		t.writeRow(&b, widths, row, func(column int, cell string) string {
+			rules := append(append([]MarkRule(nil), t.marker.rules...), t.columnRules[column]...)
+			return t.marker.renderer.Render(markSegmentsOf(cell, rules, t.marker.overlapPolicy))
+		})
+	}
+	t.writeLine(&b, widths, t.border.BottomLeft, t.border.BottomMiddle, t.border.BottomRight)
+
+	t.rows = nil
+	_, err := io.WriteString(t.marker.out, b.String())
+	return err
+}
+
+// columnWidths returns the widest visible width of the cells in each column
+func (t *Table) columnWidths() []int {
+	var widths []int
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for column, cell := range row {
+			if column == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[column] = max(widths[column], Width(cell))
+		}
+	}
+	return widths
+}
+
+// writeLine writes a horizontal line of the border with given corners and joints
+func (t *Table) writeLine(b *strings.Builder, widths []int, left, middle, right string) {
+	if t.border.Horizontal == "" {
+		return
+	}
+	b.WriteString(left)
+	for column, width := range widths {
+		if column > 0 {
+			b.WriteString(middle)
+		}
+		b.WriteString(strings.Repeat(t.border.Horizontal, width+2))
+	}
+	b.WriteString(right)
+	b.WriteString("\n")
+}
+
+// writeRow writes the cells of a row rendered by render and aligned in their columns
+func (t *Table) writeRow(b *strings.Builder, widths []int, row []string, render func(column int, cell string) string) {
+	bordered := t.border.Vertical != ""
+	if bordered {
+		b.WriteString(t.border.Vertical + " ")
+	}
+	for column, width := range widths {
+		cell := ""
+		if column < len(row) {
+			cell = row[column]
+		}
+		if column > 0 {
+			if bordered {
+				b.WriteString(" " + t.border.Vertical + " ")
+			} else {
+				b.WriteString("  ")
+			}
+		}
+
+		padding := width - Width(cell)
+		if !bordered && column == len(widths)-1 && t.alignments[column] == AlignLeft {
+			// spaces at the end of a line are not needed without a border to align
+			padding = 0
+		}
+		b.WriteString(pad(render(column, cell), padding, t.alignments[column]))
+	}
+	if bordered {
+		b.WriteString(" " + t.border.Vertical)
+	}
+	b.WriteString("\n")
+}
+
+// matchWhole matches the whole string
+var matchWhole = SpanMatcherFunc(func(str string) []Span {
+	if str == "" {
+		return nil
+	}
+	return []Span{{Start: 0, End: len(str), Text: str}}
+})
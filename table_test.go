@@ -0,0 +1,75 @@
+package marker
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newServiceTable(out *bytes.Buffer, options ...WriteMarkerOption) *Table {
+	return NewTable(out, options...).
+		SetHeader("SERVICE", "LATENCY", "STATE").
+		SetAlignment(1, AlignRight).
+		AddRule(MarkRule{Matcher: MatchAll("down"), Style: Fg(Red)}).
+		AddColumnRule(2, MarkRule{Matcher: MatchAll("up"), Style: Fg(Green)}).
+		Append("api", "12ms", "up").
+		Append("データ", "1.2s", "down").
+		Append("backup", "", "upgrading")
+}
+
+func Test_Table(t *testing.T) {
+	var out bytes.Buffer
+	table := newServiceTable(&out, WithColorMode(ColorAlways), WithColorDepth(Depth16))
+	assert.Nil(t, table.Flush())
+
+	expected := "SERVICE  LATENCY  STATE\n" +
+		"api         12ms  \x1b[32mup\x1b[0m\n" +
+		"データ      1.2s  \x1b[31mdown\x1b[0m\n" +
+		"backup            \x1b[32mup\x1b[0mgrading\n"
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	assert.Nil(t, table.Flush())
+	assert.Equal(t, "SERVICE  LATENCY  STATE\n", out.String(), "rows are cleared by Flush")
+}
+
+func Test_TableBorder(t *testing.T) {
+	var out bytes.Buffer
+	table := newServiceTable(&out, WithRenderer(PlainRenderer{})).
+		SetBorder(BorderLight).
+		SetAlignment(2, AlignCenter).
+		AddRule(MarkRule{Matcher: MatchRegexp(regexp.MustCompile(`\d+`)), Style: Fg(Red)})
+	assert.Nil(t, table.Flush())
+
+	expected := "┌─────────┬─────────┬───────────┐\n" +
+		"│ SERVICE │ LATENCY │   STATE   │\n" +
+		"├─────────┼─────────┼───────────┤\n" +
+		"│ api     │    12ms │    up     │\n" +
+		"│ データ  │    1.2s │   down    │\n" +
+		"│ backup  │         │ upgrading │\n" +
+		"└─────────┴─────────┴───────────┘\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func Test_TableHeaderStyle(t *testing.T) {
+	var out bytes.Buffer
+	table := NewTable(&out, WithColorMode(ColorAlways), WithColorDepth(DepthTrueColor)).
+		SetBorder(BorderASCII).
+		SetHeader("KEY", "VALUE").
+		SetHeaderStyle(TextStyle{Attrs: Bold}).
+		Append("\x1b[36mid\x1b[0m", "42", "extra")
+	assert.Nil(t, table.Flush())
+
+	expected := "+-----+-------+-------+\n" +
+		"| \x1b[1mKEY\x1b[0m | \x1b[1mVALUE\x1b[0m |       |\n" +
+		"+-----+-------+-------+\n" +
+		"| \x1b[36mid\x1b[0m  | 42    | extra |\n" +
+		"+-----+-------+-------+\n"
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	assert.Nil(t, NewTable(&out).Flush())
+	assert.Empty(t, out.String())
+}
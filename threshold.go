@@ -0,0 +1,114 @@
+package marker
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationRegexp = regexp.MustCompile(`[-+]?(?:(?:\d*\.\d+|\d+)(?:ns|us|µs|μs|ms|s|m|h))+`)
+	byteSizeRegexp = regexp.MustCompile(`\d+(?:\.\d+)? ?(?:[kKMGTPE]i?)?B`)
+)
+
+// byteSizeExponents are the exponents of the multipliers that the prefixes of byte size units stand for
+var byteSizeExponents = map[byte]float64{'k': 1, 'K': 1, 'M': 2, 'G': 3, 'T': 4, 'P': 5, 'E': 6}
+
+// MatchDuration creates a SpanMatcherFunc that matches durations in the syntax of time.ParseDuration,
+// such as 812ms, 1.5s, .5s, -5s and 1h30m
+func MatchDuration() SpanMatcherFunc {
+	return matchTokens(durationRegexp, numberJoiners, numberJoiners, func(token string) string {
+		if _, err := time.ParseDuration(token); err != nil {
+			return ""
+		}
+		return token
+	})
+}
+
+// MatchByteSize creates a SpanMatcherFunc that matches byte sizes such as 512B, 1.5 KB, 20MiB and 4GB
+func MatchByteSize() SpanMatcherFunc {
+	return matchTokens(byteSizeRegexp, numberJoiners, numberJoiners, identity)
+}
+
+// ParseByteSize returns the number of bytes of a byte size such as 1.5 KB or 20MiB. Units with i, such as KiB,
+// are powers of 1024, while the others, such as KB, are powers of 1000.
+func ParseByteSize(str string) (int64, error) {
+	number := strings.TrimRight(str, " kKMGTPEiB")
+	unit := strings.TrimSpace(str[len(number):])
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || !strings.HasSuffix(unit, "B") {
+		return 0, fmt.Errorf("invalid byte size %q", str)
+	}
+
+	prefix := strings.TrimSuffix(unit, "B")
+	base, exponent := 1000.0, 0.0
+	if strings.HasSuffix(prefix, "i") {
+		prefix, base = strings.TrimSuffix(prefix, "i"), 1024
+		if prefix == "" {
+			return 0, fmt.Errorf("invalid byte size %q", str)
+		}
+	}
+	if prefix != "" {
+		var ok bool
+		if exponent, ok = byteSizeExponents[prefix[0]]; !ok || len(prefix) > 1 {
+			return 0, fmt.Errorf("invalid byte size %q", str)
+		}
+	}
+	return int64(math.Round(value * math.Pow(base, exponent))), nil
+}
+
+// DurationThreshold is the style of the durations longer than Above
+type DurationThreshold struct {
+	Above time.Duration
+	Style Style
+}
+
+// DurationStyle creates a StyleFunc that styles each duration span with the style of the highest threshold it is
+// longer than, or with fallback if it is longer than none. It is meant to be used along with MatchDuration:
+//
+//	MarkRule{Matcher: MatchDuration(), StyleFunc: DurationStyle(Fg(Green),
+//		DurationThreshold{Above: 500 * time.Millisecond, Style: Fg(Red)},
+//		DurationThreshold{Above: 100 * time.Millisecond, Style: Fg(Yellow)},
+//	)}
+func DurationStyle(fallback Style, thresholds ...DurationThreshold) StyleFunc {
+	return func(span Span) Style {
+		duration, err := time.ParseDuration(span.Text)
+		if err != nil {
+			return fallback
+		}
+		style, highest := fallback, time.Duration(math.MinInt64)
+		for _, threshold := range thresholds {
+			if duration > threshold.Above && threshold.Above >= highest {
+				style, highest = threshold.Style, threshold.Above
+			}
+		}
+		return style
+	}
+}
+
+// SizeThreshold is the style of the byte sizes larger than Above bytes
+type SizeThreshold struct {
+	Above int64
+	Style Style
+}
+
+// SizeStyle creates a StyleFunc that styles each byte size span with the style of the highest threshold it is
+// larger than, or with fallback if it is larger than none. It is meant to be used along with MatchByteSize.
+func SizeStyle(fallback Style, thresholds ...SizeThreshold) StyleFunc {
+	return func(span Span) Style {
+		size, err := ParseByteSize(span.Text)
+		if err != nil {
+			return fallback
+		}
+		style, highest := fallback, int64(math.MinInt64)
+		for _, threshold := range thresholds {
+			if size > threshold.Above && threshold.Above >= highest {
+				style, highest = threshold.Style, threshold.Above
+			}
+		}
+		return style
+	}
+}
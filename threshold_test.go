@@ -0,0 +1,76 @@
+package marker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatchDuration(t *testing.T) {
+	str := "latency=812ms took 1.5s, 1h30m5s and 250µs, skew -5s, +2m and .5s (not 5min, 10 ms, v1.5s, x-5s or 2019-10-18)"
+	expected := []string{"812ms", "1.5s", "1h30m5s", "250µs", "-5s", "+2m", ".5s"}
+	assert.Equal(t, expected, spanTexts(t, MatchDuration(), str))
+}
+
+func Test_MatchByteSize(t *testing.T) {
+	str := "read 512B, 1.5 KB and 20MiB of 4GB (not 3 B-trees, 5Mb, 10KiBs or id=7B1)"
+	assert.Equal(t, []string{"512B", "1.5 KB", "20MiB", "4GB"}, spanTexts(t, MatchByteSize(), str))
+}
+
+func Test_ParseByteSize(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected int64
+	}{
+		{str: "512B", expected: 512},
+		{str: "1.5 KB", expected: 1500},
+		{str: "2kB", expected: 2000},
+		{str: "20MiB", expected: 20 << 20},
+		{str: "4GB", expected: 4000000000},
+		{str: "1TiB", expected: 1 << 40},
+	}
+	for _, testCase := range tests {
+		size, err := ParseByteSize(testCase.str)
+		assert.Nil(t, err, testCase.str)
+		assert.Equal(t, testCase.expected, size, testCase.str)
+	}
+
+	for _, str := range []string{"", "MB", "5", "5 iB", "5XB", "5KMB", "five KB"} {
+		_, err := ParseByteSize(str)
+		assert.EqualError(t, err, `invalid byte size "`+str+`"`)
+	}
+}
+
+func Test_DurationStyle(t *testing.T) {
+	rule := MarkRule{Matcher: MatchDuration(), StyleFunc: DurationStyle(Fg(Green),
+		DurationThreshold{Above: 100 * time.Millisecond, Style: Fg(Yellow)},
+		DurationThreshold{Above: 500 * time.Millisecond, Style: Fg(Red)},
+	)}
+
+	expected := map[string]TextStyle{
+		"812ms": Fg(Red),
+		"1m":    Fg(Red),
+		"250ms": Fg(Yellow),
+		"500ms": Fg(Yellow),
+		"100ms": Fg(Green),
+		"90µs":  Fg(Green),
+	}
+	assert.Equal(t, expected, styledTexts("latency=812ms 1m 250ms 500ms 100ms 90µs", []MarkRule{rule}))
+}
+
+func Test_SizeStyle(t *testing.T) {
+	rule := MarkRule{Matcher: MatchByteSize(), StyleFunc: SizeStyle(Fg(Green),
+		SizeThreshold{Above: 1 << 30, Style: Fg(Red)},
+		SizeThreshold{Above: 1 << 20, Style: Fg(Yellow)},
+	)}
+
+	expected := map[string]TextStyle{
+		"2GiB":   Fg(Red),
+		"1.5GB":  Fg(Red),
+		"20MiB":  Fg(Yellow),
+		"1MiB":   Fg(Green),
+		"512 KB": Fg(Green),
+	}
+	assert.Equal(t, expected, styledTexts("2GiB 1.5GB 20MiB 1MiB 512 KB", []MarkRule{rule}))
+}
@@ -0,0 +1,63 @@
+package marker
+
+import (
+	"io"
+	"regexp"
+)
+
+var (
+	// sgrRegexp matches Select Graphic Rendition sequences, which are the only sequences that marking writes
+	sgrRegexp = regexp.MustCompile(`\x1b\[[0-9;:]*m`)
+	// partialSGRRegexp matches the beginning of an SGR sequence at the end of a write
+	partialSGRRegexp = regexp.MustCompile(`\x1b(?:\[[0-9;:]*)?$`)
+)
+
+// Unmark removes the SGR sequences that style given string, so that Unmark(Mark(s, matcher, style)) == s
+// for any string s without SGR sequences. Other escape sequences, such as cursor movements, are kept.
+func Unmark(str string) string {
+	return sgrRegexp.ReplaceAllString(str, "")
+}
+
+// Strip removes all escape sequences from given string and returns the text that a terminal would show
+func Strip(str string) string {
+	return ansiRegexp.ReplaceAllString(str, "")
+}
+
+// UnmarkWriter removes the SGR sequences from the text written to it, like Unmark, and writes the rest to its out.
+// A sequence split between writes is removed as a whole, so the beginning of it is held until the next write.
+type UnmarkWriter struct {
+	out     io.Writer
+	pending []byte
+}
+
+// NewUnmarkWriter creates an UnmarkWriter that writes out to the given io.Writer
+func NewUnmarkWriter(writer io.Writer) *UnmarkWriter {
+	return &UnmarkWriter{out: writer}
+}
+
+// Write removes the SGR sequences from p and writes the rest to out
+func (u *UnmarkWriter) Write(p []byte) (n int, err error) {
+	data := append(u.pending, p...)
+	complete := len(data)
+	if partial := partialSGRRegexp.FindIndex(data); partial != nil {
+		complete = partial[0]
+	}
+	u.pending = append([]byte(nil), data[complete:]...)
+
+	if unmarked := sgrRegexp.ReplaceAll(data[:complete], nil); len(unmarked) > 0 {
+		if _, err := u.out.Write(unmarked); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the beginning of a sequence held from the last write, which is not removed as it was never completed
+func (u *UnmarkWriter) Flush() error {
+	if len(u.pending) == 0 {
+		return nil
+	}
+	_, err := u.out.Write(u.pending)
+	u.pending = nil
+	return err
+}
@@ -0,0 +1,103 @@
+package marker
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Unmark(t *testing.T) {
+	assert.Equal(t, "Skydome is a data company.", Unmark("\x1b[31mSkydome\x1b[0m is a \x1b[1;38;5;208mdata\x1b[0m company."))
+	assert.Equal(t, "\x1b[2KSkydome\x1b]0;title\x07", Unmark("\x1b[2K\x1b[4:3mSkydome\x1b[m\x1b]0;title\x07"))
+	assert.Equal(t, "no escapes", Unmark("no escapes"))
+}
+
+func Test_Strip(t *testing.T) {
+	assert.Equal(t, "Skydome", Strip("\x1b]0;title\x07\x1b[2K\x1b[31mSkydome\x1b[0m\x1b7"))
+}
+
+func Test_UnmarkMarked(t *testing.T) {
+	str := "[INFO] 2006-01-02T15:04:05+07:00 Monday (api) admin@example.com wrote id=42 to " +
+		"logs.txt at 3:04PM, ERROR ERROR ERROR\n\tünïcode [nested (spans)] Friday from [::1]:80 " +
+		"and 10.0.0.1 in 10.0.0.0/8 via https://example.com/a?b=c#d. at 3aee3da for " +
+		"123e4567-e89b-12d3-a456-426614174000 01ARZ3NDEKTSV4RRFFQ69G5FAV da39a3ee5e6b4b0d3255bfef95601890afd80709 " +
+		"in 12.5ms -3 0x1F 1,000 99.9% 1h30m 1.5 KB 20MiB"
+	matchers := []Matcher{
+		MatchAll("ERROR"),
+		MatchN("ERROR", 2),
+		MatchMultiple([]string{"id", "logs"}),
+		MatchRegexp(regexp.MustCompile(`id=\d+`)),
+		MatchRegexpGroups(regexp.MustCompile(`(?P<key>\w+)=(?P<value>\d+)`), map[string]Style{"key": Fg(Cyan)}),
+		MatchTimestamp(time.RFC3339),
+		MatchTimestamp(time.Kitchen),
+		MatchSurrounded("ü", "e"),
+		MatchBracketSurrounded(),
+		MatchParensSurrounded(),
+		MatchEmail(),
+		MatchDaysOfWeek(),
+		MatchIP(),
+		MatchCIDR(),
+		MatchHostPort(),
+		MatchURL(),
+		MatchURLParts(map[string]Style{"host": Fg(Green), "query": Fg(Yellow)}),
+		MatchUUID(),
+		MatchGitSHA(),
+		MatchSHA1(),
+		MatchSHA256(),
+		MatchULID(),
+		MatchNumber(),
+		MatchNumber(CommonUnits...),
+		MatchDuration(),
+		MatchByteSize(),
+	}
+	styles := []Style{Fg(Red), Fg(Color256(208)).With(Bold), TextStyle{Fg: RGB(1, 2, 3), Bg: Blue}.UnderlinedIn(Red)}
+
+	for i, matcher := range matchers {
+		for _, style := range styles {
+			assert.Equal(t, str, Unmark(Mark(str, matcher, style)), "matcher %d", i)
+			assert.Equal(t, str, Unmark(MarkFunc(str, matcher, func(Span) Style { return style })), "matcher %d", i)
+		}
+	}
+	assert.Equal(t, str, Unmark(MarkMany(str, Fg(Red), matchers...)))
+
+	rules := make([]MarkRule, len(matchers))
+	for i, matcher := range matchers {
+		rules[i] = MarkRule{Matcher: matcher, Style: styles[i%len(styles)]}
+	}
+	for _, depth := range []ColorDepth{DepthTrueColor, Depth256, Depth16, DepthNone} {
+		assert.Equal(t, str, Unmark(Render(str, ANSIRenderer{Depth: depth}, rules...)))
+	}
+	for _, policy := range []OverlapPolicy{OverlapSplit, OverlapFirstRule, OverlapLongest, OverlapPriority} {
+		assert.Equal(t, str, Unmark(markRules(str, rules, policy)))
+	}
+}
+
+func Test_UnmarkWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewUnmarkWriter(&out)
+
+	marked := Mark("Skydome is a data company.", MatchAll("data"), Fg(Color256(208)).With(Bold))
+	for _, chunk := range []string{marked[:9], marked[9:15], marked[15:16], marked[16:]} {
+		n, err := writer.Write([]byte(chunk))
+		assert.Nil(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.Equal(t, "Skydome is a data company.", out.String())
+
+	_, err := writer.Write([]byte("cut \x1b[3"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Skydome is a data company.cut ", out.String())
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, "Skydome is a data company.cut \x1b[3", out.String())
+
+	out.Reset()
+	writeMarker := NewWriteMarker(NewUnmarkWriter(&out))
+	writeMarker.AddRule(MarkRule{Matcher: MatchAll("ERROR"), Style: Fg(Red)})
+	_, err = writeMarker.Write([]byte("ERROR: " + strings.Repeat("x", 3)))
+	assert.Nil(t, err)
+	assert.Equal(t, "ERROR: xxx", out.String())
+}
@@ -0,0 +1,106 @@
+package marker
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// urlRegexp matches the candidates of URLs, which are trimmed of the punctuation that follows them in text
+var urlRegexp = regexp.MustCompile("[A-Za-z][A-Za-z0-9+.-]*://[^\\s<>\"`]*")
+
+// urlParts are the parts of a URL that URLPartMatcher styles, in their order in a URL
+var urlParts = []string{"scheme", "host", "path", "query", "fragment"}
+
+// MatchURL creates a SpanMatcherFunc that matches URLs with any scheme, such as http, https, ws, wss, file or
+// a custom one like s3. Punctuation that ends a sentence and closing parentheses without an opening one in the URL,
+// as in "(see https://example.com/docs).", are not matched. A URL may follow text in other scripts without a space,
+// as in "见https://example.com", but not an ASCII word such as "_http://example.com".
+func MatchURL() SpanMatcherFunc {
+	return func(str string) []Span {
+		var spans []Span
+		for _, index := range urlRegexp.FindAllStringIndex(str, -1) {
+			start := index[0]
+			if start > 0 && str[start-1] < utf8.RuneSelf && isWordByte(str[start-1]) {
+				continue
+			}
+			if u := trimURL(str[start:index[1]]); u != "" {
+				spans = append(spans, Span{Start: start, End: start + len(u), Text: u})
+			}
+		}
+		return spans
+	}
+}
+
+// URLPartMatcher is a StyleMatcher that styles the parts of URLs separately
+type URLPartMatcher struct {
+	styles map[string]Style
+}
+
+// MatchURLParts creates a URLPartMatcher that matches URLs as MatchURL does and styles their parts with the styles
+// keyed by "scheme" (with ://), "host" (with the user and the port), "path", "query" (with ?) and "fragment" (with #).
+// Parts without a style are marked with the style given along with the matcher.
+func MatchURLParts(styles map[string]Style) *URLPartMatcher {
+	return &URLPartMatcher{styles: styles}
+}
+
+// Spans returns the styled parts and the rest of each URL as separate spans.
+// The spans of parts have the key of their style as "group" in Meta.
+func (m *URLPartMatcher) Spans(str string) []Span {
+	var spans []Span
+	for _, span := range MatchURL()(str) {
+		owners := make([]string, len(span.Text))
+		for i, bounds := range urlPartBounds(span.Text) {
+			if _, ok := m.styles[urlParts[i]]; !ok {
+				continue
+			}
+			for j := bounds[0]; j < bounds[1]; j++ {
+				owners[j] = urlParts[i]
+			}
+		}
+		spans = append(spans, ownerSpans(str, span.Start, owners)...)
+	}
+	return spans
+}
+
+// SpanStyle returns the style of the part of given span
+func (m *URLPartMatcher) SpanStyle(span Span) Style {
+	return m.styles[span.Meta["group"]]
+}
+
+// trimURL returns given candidate without the punctuation at its end, or an empty string if it is not a URL
+func trimURL(candidate string) string {
+	for candidate != "" {
+		last := candidate[len(candidate)-1]
+		if strings.IndexByte(".,:;!?'*", last) < 0 && !unbalancedCloser(candidate, last) {
+			break
+		}
+		candidate = candidate[:len(candidate)-1]
+	}
+	if strings.HasSuffix(candidate, "://") {
+		return ""
+	}
+	if _, err := url.Parse(candidate); err != nil {
+		return ""
+	}
+	return candidate
+}
+
+// unbalancedCloser reports whether given byte closes a parenthesis or a bracket that is not opened in the string
+func unbalancedCloser(str string, closer byte) bool {
+	opener := map[byte]string{')': "(", ']': "[", '}': "{"}[closer]
+	return opener != "" && strings.Count(str, opener) < strings.Count(str, string(closer))
+}
+
+// urlPartBounds returns the start and the end of each part of given URL in the order of urlParts
+func urlPartBounds(u string) [][2]int {
+	hostStart := strings.Index(u, "://") + len("://")
+	hostEnd := hostStart + strings.IndexAny(u[hostStart:]+"/", "/?#")
+	pathEnd := hostEnd + strings.IndexAny(u[hostEnd:]+"?", "?#")
+	queryEnd := pathEnd
+	if strings.HasPrefix(u[pathEnd:], "?") {
+		queryEnd = pathEnd + strings.IndexAny(u[pathEnd:]+"#", "#")
+	}
+	return [][2]int{{0, hostStart}, {hostStart, hostEnd}, {hostEnd, pathEnd}, {pathEnd, queryEnd}, {queryEnd, len(u)}}
+}
@@ -0,0 +1,52 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatchURL(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected []string
+	}{
+		{str: "GET https://example.com/api/v1?id=42#top done", expected: []string{"https://example.com/api/v1?id=42#top"}},
+		{str: "see http://example.com, wss://ws.example.com:8443/feed. and file:///var/log/app.log!",
+			expected: []string{"http://example.com", "wss://ws.example.com:8443/feed", "file:///var/log/app.log"}},
+		{str: "(docs at https://example.com/docs) and https://en.wikipedia.org/wiki/Go_(programming_language).",
+			expected: []string{"https://example.com/docs", "https://en.wikipedia.org/wiki/Go_(programming_language)"}},
+		{str: `href="s3://bucket/key" <ws://localhost:8080/> 'ftp://user@host/file?'`,
+			expected: []string{"s3://bucket/key", "ws://localhost:8080/", "ftp://user@host/file"}},
+		{str: "链接见https://example.com/文档 或 ftp://example.com", expected: []string{"https://example.com/文档", "ftp://example.com"}},
+		{str: "not urls: https:// and ://example.com and _http://example.com or 1http://example.com", expected: nil},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, spanTexts(t, MatchURL(), testCase.str), testCase.str)
+	}
+}
+
+func Test_MatchURLParts(t *testing.T) {
+	str := "open https://user@example.com:8080/a/b?q=1&r=2#frag now, file:///tmp?x"
+	matcher := MatchURLParts(map[string]Style{
+		"scheme": Fg(Blue),
+		"host":   Fg(Green),
+		"query":  Fg(Yellow),
+	})
+
+	expectedSpans := []Span{
+		{Start: 5, End: 13, Text: "https://", Meta: map[string]string{"group": "scheme"}},
+		{Start: 13, End: 34, Text: "user@example.com:8080", Meta: map[string]string{"group": "host"}},
+		{Start: 34, End: 38, Text: "/a/b"},
+		{Start: 38, End: 46, Text: "?q=1&r=2", Meta: map[string]string{"group": "query"}},
+		{Start: 46, End: 51, Text: "#frag"},
+		{Start: 57, End: 64, Text: "file://", Meta: map[string]string{"group": "scheme"}},
+		{Start: 64, End: 68, Text: "/tmp"},
+		{Start: 68, End: 70, Text: "?x", Meta: map[string]string{"group": "query"}},
+	}
+	assert.Equal(t, expectedSpans, matcher.Spans(str))
+
+	marked := Mark("at https://example.com/docs", matcher, TextStyle{Attrs: Underline})
+	assert.Equal(t, "at \x1b[34mhttps://\x1b[0m\x1b[32mexample.com\x1b[0m\x1b[4m/docs\x1b[0m", marked)
+}
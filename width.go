@@ -0,0 +1,99 @@
+package marker
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Alignment is the position of a string padded to a width
+type Alignment int
+
+// Alignments of padded strings, AlignLeft is the default
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+// Width returns the number of terminal cells that given string takes. Escape sequences take no cells,
+// while wide runes such as CJK characters and emoji take two.
+func Width(str string) int {
+	visible, _ := splitEscapes(str)
+	return runewidth.StringWidth(visible)
+}
+
+// cellWidth returns the cells that given rune takes at the column, where tabs move to the next tab stop
+func cellWidth(r rune, column int) int {
+	if r == '\t' {
+		return tabWidth - column%tabWidth
+	}
+	return runewidth.RuneWidth(r)
+}
+
+// Truncate cuts given string to the width with tail, such as "…", at its end when it is wider than width.
+// Escape sequences before the cut are kept and the styles open at the cut are reset after the tail.
+func Truncate(str string, width int, tail string) string {
+	if Width(str) <= width {
+		return str
+	}
+	tailWidth := Width(tail)
+	if tailWidth > width {
+		tail, tailWidth = "", 0
+	}
+
+	visible, escapes := splitEscapes(str)
+	cut, cells := 0, 0
+	for i, r := range visible {
+		runeWidth := runewidth.RuneWidth(r)
+		if cells+runeWidth > width-tailWidth {
+			break
+		}
+		cut, cells = i+len(string(r)), cells+runeWidth
+	}
+
+	var b strings.Builder
+	var active []string
+	last := 0
+	for _, escape := range escapes {
+		// a reset right at the cut closes the text before it, other sequences there belong to the text after it
+		if escape.offset > cut || escape.offset == cut && !isReset(escape.sequence) {
+			continue
+		}
+		b.WriteString(visible[last:escape.offset])
+		b.WriteString(escape.sequence)
+		last = escape.offset
+		active = activeSequences(active, escape.sequence)
+	}
+	b.WriteString(visible[last:cut])
+	b.WriteString(tail)
+	if len(active) > 0 {
+		b.WriteString(resetSequence)
+	}
+	return b.String()
+}
+
+// Pad fills given string with spaces to the width, aligned as given. Strings wider than width are kept as they are.
+func Pad(str string, width int, align Alignment) string {
+	return pad(str, width-Width(str), align)
+}
+
+// pad adds given number of spaces around the string as the alignment places it
+func pad(str string, padding int, align Alignment) string {
+	if padding <= 0 {
+		return str
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + str
+	case AlignCenter:
+		return strings.Repeat(" ", padding/2) + str + strings.Repeat(" ", padding-padding/2)
+	}
+	return str + strings.Repeat(" ", padding)
+}
+
+// isReset reports whether given escape sequence is an SGR sequence that turns off all styles
+func isReset(sequence string) bool {
+	parameters, ok := sgrParameters(sequence)
+	return ok && resetsGraphics(parameters)
+}
@@ -0,0 +1,65 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Width(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected int
+	}{
+		{str: "", expected: 0},
+		{str: "Skydome", expected: 7},
+		{str: "\x1b[1;31mSkydome\x1b[0m", expected: 7},
+		{str: "\x1b]0;title\x07\x1b[38;2;255;135;0mdata\x1b[0m", expected: 4},
+		{str: "ünïcode", expected: 7},
+		{str: "日本語", expected: 6},
+		{str: "\x1b[32m✓\x1b[0m 🚀", expected: 4},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, Width(testCase.str), "%q", testCase.str)
+	}
+}
+
+func Test_Truncate(t *testing.T) {
+	tests := []struct {
+		str      string
+		width    int
+		tail     string
+		expected string
+	}{
+		{str: "Skydome", width: 7, tail: "…", expected: "Skydome"},
+		{str: "Skydome", width: 5, tail: "…", expected: "Skyd…"},
+		{str: "Skydome", width: 5, tail: "", expected: "Skydo"},
+		{str: "Skydome", width: 2, tail: "...", expected: "Sk"},
+		{str: "Skydome", width: 0, tail: "…", expected: ""},
+		{str: "\x1b[31mSkydome\x1b[0m is a data company", width: 10, tail: "…",
+			expected: "\x1b[31mSkydome\x1b[0m i…"},
+		{str: "\x1b[31mSkydome\x1b[0m is a data company", width: 5, tail: "…",
+			expected: "\x1b[31mSkyd…\x1b[0m"},
+		{str: "\x1b[31mSkydome\x1b[0m\x1b[1m is\x1b[0m", width: 7, tail: "",
+			expected: "\x1b[31mSkydome\x1b[0m"},
+		{str: "\x1b[0;31mhello world", width: 5, tail: "…", expected: "\x1b[0;31mhell…\x1b[0m"},
+		{str: "\x1b[31mhello\x1b[0m \x1b[0mworld", width: 8, tail: "…", expected: "\x1b[31mhello\x1b[0m \x1b[0mw…"},
+		{str: "日本語のテキスト", width: 6, tail: "…", expected: "日本…"},
+		{str: "日本語のテキスト", width: 5, tail: "", expected: "日本"},
+	}
+
+	for _, testCase := range tests {
+		actual := Truncate(testCase.str, testCase.width, testCase.tail)
+		assert.Equal(t, testCase.expected, actual, "%q %d", testCase.str, testCase.width)
+	}
+}
+
+func Test_Pad(t *testing.T) {
+	marked := Mark("data", MatchAll("data"), Fg(Red))
+	assert.Equal(t, "\x1b[31mdata\x1b[0m    ", Pad(marked, 8, AlignLeft))
+	assert.Equal(t, "    \x1b[31mdata\x1b[0m", Pad(marked, 8, AlignRight))
+	assert.Equal(t, "  \x1b[31mdata\x1b[0m   ", Pad(marked, 9, AlignCenter))
+	assert.Equal(t, "日本 ", Pad("日本", 5, AlignLeft))
+	assert.Equal(t, marked, Pad(marked, 2, AlignRight))
+}
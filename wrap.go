@@ -0,0 +1,136 @@
+package marker
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// lineBreak replaces the text between start and end, the spaces between two words, with a new line.
+// The indentation of a line is dropped without a new line when the word after it only fits without it.
+type lineBreak struct {
+	start  int
+	end    int
+	indent bool
+}
+
+// Wrap wraps given marked string at the width in cells on word boundaries, and breaks the words wider than a line.
+// Styles open at a line break are reset before it and opened again after it, so a marked span crossing the break
+// keeps its style on the next line. The spaces at line breaks are dropped, while the existing new lines are kept.
+func Wrap(str string, width int) string {
+	if width <= 0 {
+		return str
+	}
+	visible, escapes := splitEscapes(str)
+	breaks := lineBreaks(visible, width)
+	if len(breaks) == 0 {
+		return str
+	}
+
+	var b strings.Builder
+	var active []string
+	last, e := 0, 0
+	for _, lineBreak := range breaks {
+		// escapes in the dropped spaces and resets right after them are written before the break
+		for ; e < len(escapes); e++ {
+			escape := escapes[e]
+			if escape.offset > lineBreak.end || escape.offset == lineBreak.end && !isReset(escape.sequence) {
+				break
+			}
+			offset := escape.offset
+			if offset > lineBreak.start {
+				offset = lineBreak.start
+			}
+			b.WriteString(visible[last:offset])
+			last = offset
+			b.WriteString(escape.sequence)
+			active = activeSequences(active, escape.sequence)
+		}
+		b.WriteString(visible[last:lineBreak.start])
+		last = lineBreak.end
+		if lineBreak.indent {
+			continue
+		}
+		if len(active) > 0 {
+			b.WriteString(resetSequence)
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.Join(active, ""))
+	}
+	for ; e < len(escapes); e++ {
+		b.WriteString(visible[last:escapes[e].offset])
+		b.WriteString(escapes[e].sequence)
+		last = escapes[e].offset
+	}
+	b.WriteString(visible[last:])
+	return b.String()
+}
+
+// lineBreaks returns where given visible text breaks to fit in the width.
+// A word goes to the next line with the spaces before it dropped when it does not fit in the current line,
+// and a word wider than the width is broken where the line is full. The indentation of a line is dropped
+// instead of breaking a word that fits in the width by itself.
+func lineBreaks(visible string, width int) []lineBreak {
+	var breaks []lineBreak
+	column := 0
+	for i := 0; i < len(visible); {
+		r, size := utf8.DecodeRuneInString(visible[i:])
+		switch {
+		case r == '\n':
+			column = 0
+			i += size
+		case isWrapSpace(r):
+			end, spaceColumn := i, column
+			for ; end < len(visible) && isWrapSpace(rune(visible[end])); end++ {
+				spaceColumn += cellWidth(rune(visible[end]), spaceColumn)
+			}
+			wordEnd := wordEndOf(visible, end)
+			wordWidth := runewidth.StringWidth(visible[end:wordEnd])
+			if wordEnd > end && spaceColumn+wordWidth > width && (column > 0 || wordWidth <= width) {
+				breaks = append(breaks, lineBreak{start: i, end: end, indent: column == 0})
+				spaceColumn = 0
+			}
+			column, i = spaceColumn, end
+		default:
+			runeWidth := runewidth.RuneWidth(r)
+			if column > 0 && column+runeWidth > width {
+				breaks = append(breaks, lineBreak{start: i, end: i})
+				column = 0
+			}
+			column += runeWidth
+			i += size
+		}
+	}
+	return breaks
+}
+
+// wordEndOf returns the end of the word that starts at given offset of the visible text
+func wordEndOf(visible string, start int) int {
+	for end := start; end < len(visible); end++ {
+		if visible[end] == '\n' || isWrapSpace(rune(visible[end])) {
+			return end
+		}
+	}
+	return len(visible)
+}
+
+func isWrapSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// activeSequences returns the SGR sequences in effect after given escape sequence is written,
+// which are the sequences written since the last reset
+func activeSequences(active []string, sequence string) []string {
+	parameters, ok := sgrParameters(sequence)
+	switch {
+	case !ok:
+		return active
+	case !resetsGraphics(parameters):
+		return append(active, sequence)
+	case strings.Trim(strings.Join(parameters, ""), "0") == "":
+		return nil
+	}
+	// a sequence such as 0;31 resets and then sets a style
+	return []string{sequence}
+}
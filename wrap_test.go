@@ -0,0 +1,79 @@
+package marker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Wrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		width    int
+		expected string
+	}{
+		{name: "fits", str: "Skydome is a data company.", width: 26, expected: "Skydome is a data company."},
+		{name: "words", str: "Skydome is a data company.", width: 12, expected: "Skydome is a\ndata\ncompany."},
+		{name: "spaces", str: "Skydome   is a  data", width: 10, expected: "Skydome\nis a  data"},
+		{name: "long word", str: "id 0123456789abcdef", width: 8, expected: "id\n01234567\n89abcdef"},
+		{name: "new lines", str: "Skydome is\na data company.", width: 10, expected: "Skydome is\na data\ncompany."},
+		{name: "wide runes", str: "日本語のテキスト", width: 5, expected: "日本\n語の\nテキ\nスト"},
+		{name: "indentation", str: "\tSkydome is", width: 16, expected: "\tSkydome\nis"},
+		{name: "indented long word", str: "   verylongword is", width: 12, expected: "verylongword\nis"},
+		{name: "indented wider word", str: "  0123456789", width: 8, expected: "  012345\n6789"},
+		{name: "indented line", str: "Skydome\n    verylongword", width: 12, expected: "Skydome\nverylongword"},
+		{name: "no width", str: "Skydome is a data company.", width: 0, expected: "Skydome is a data company."},
+		{
+			name:     "style spanning the break",
+			str:      "Skydome \x1b[31mis a data\x1b[0m company.",
+			width:    12,
+			expected: "Skydome \x1b[31mis a\x1b[0m\n\x1b[31mdata\x1b[0m\ncompany.",
+		},
+		{
+			name:     "style ending at the break",
+			str:      "\x1b[31mSkydome\x1b[0m \x1b[1mis\x1b[0m",
+			width:    8,
+			expected: "\x1b[31mSkydome\x1b[0m\n\x1b[1mis\x1b[0m",
+		},
+		{
+			name:     "layered styles",
+			str:      "\x1b[44m\x1b[31mSkydome\x1b[0m\x1b[44m is\x1b[0m",
+			width:    7,
+			expected: "\x1b[44m\x1b[31mSkydome\x1b[0m\x1b[44m\x1b[0m\n\x1b[44mis\x1b[0m",
+		},
+		{
+			name:     "broken word",
+			str:      "\x1b[0;32m0123456789\x1b[0m",
+			width:    4,
+			expected: "\x1b[0;32m0123\x1b[0m\n\x1b[0;32m4567\x1b[0m\n\x1b[0;32m89\x1b[0m",
+		},
+	}
+
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, Wrap(testCase.str, testCase.width), testCase.name)
+	}
+}
+
+func Test_WrapMarked(t *testing.T) {
+	str := "[INFO] Skydome is a data company, id=42 ERROR ERROR ERROR (with nested [spans]) admin@example.com"
+	builder := &MarkBuilder{}
+	marked := builder.SetString(str).
+		Mark(MatchBracketSurrounded(), Fg(Blue)).
+		Mark(MatchParensSurrounded(), Fg(Green)).
+		Mark(MatchRegexp(regexp.MustCompile(`data company, id=\d+`)), TextStyle{Bg: Color256(236)}).
+		Mark(MatchAll("ERROR"), Fg(Red).With(Bold)).
+		Mark(MatchEmail(), TextStyle{Attrs: Underline}).
+		Build()
+
+	for width := 1; width <= Width(marked); width++ {
+		wrapped := Wrap(marked, width)
+		for _, line := range strings.Split(wrapped, "\n") {
+			assert.True(t, Width(line) <= width, "%q is wider than %d", line, width)
+		}
+		// only spaces are dropped and new lines are added
+		assert.Equal(t, strings.Join(strings.Fields(str), ""), strings.Join(strings.Fields(Unmark(wrapped)), ""), "width %d", width)
+	}
+}